@@ -0,0 +1,73 @@
+package remongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Admin wraps commands that run against the admin database.
+type Admin struct {
+	Client *mongo.Client
+}
+
+type RunningOp struct {
+	OpID             interface{} `bson:"opid"`
+	Type             string      `bson:"type"`
+	Op               string      `bson:"op"`
+	Namespace        string      `bson:"ns"`
+	Description      string      `bson:"desc"`
+	Client           string      `bson:"client"`
+	MicrosecsRunning int64       `bson:"microsecs_running"`
+	Command          bson.Raw    `bson:"command"`
+	PlanSummary      string      `bson:"planSummary"`
+}
+
+func (op RunningOp) Duration() time.Duration {
+	return time.Duration(op.MicrosecsRunning) * time.Microsecond
+}
+
+func InitAdmin(client *mongo.Client) *Admin {
+	return &Admin{Client: client}
+}
+
+func (a *Admin) GetDB() *mongo.Database {
+	return a.Client.Database("admin")
+}
+
+// ListRunningOps returns active operations that have been running for at
+// least minDuration, longest first.
+func (a *Admin) ListRunningOps(ctx context.Context, minDuration time.Duration) ([]RunningOp, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$currentOp", Value: bson.M{"allUsers": true}}},
+		{{Key: "$match", Value: bson.M{
+			"active":            true,
+			"microsecs_running": bson.M{"$gte": minDuration.Microseconds()},
+		}}},
+		{{Key: "$sort", Value: bson.M{"microsecs_running": -1}}},
+	}
+
+	cursor, err := a.GetDB().Aggregate(ctx, pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ops := []RunningOp{}
+
+	if err = cursor.All(ctx, &ops); err != nil {
+		return nil, err
+	}
+
+	return ops, nil
+}
+
+// KillOp terminates the operation with the given opid as reported by
+// ListRunningOps (an integer, or "shard:opid" behind mongos).
+func (a *Admin) KillOp(ctx context.Context, opID interface{}) error {
+	return a.GetDB().
+		RunCommand(ctx, bson.D{{Key: "killOp", Value: 1}, {Key: "op", Value: opID}}).
+		Err()
+}
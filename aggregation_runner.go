@@ -0,0 +1,139 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AggregationRunner refreshes named pipelines on an interval into their
+// target collections using $merge. Only the elected leader runs them.
+type AggregationRunner struct {
+	Leader   *LeaderElector
+	Interval time.Duration
+	// Metrics receives the duration of every run and the number of
+	// documents in the target afterwards.
+	Metrics MetricsHook
+
+	mu   sync.Mutex
+	jobs map[string]*MaterializedView
+}
+
+func InitAggregationRunner(leader *LeaderElector, interval time.Duration) *AggregationRunner {
+	return &AggregationRunner{
+		Leader:   leader,
+		Interval: interval,
+		jobs:     map[string]*MaterializedView{},
+	}
+}
+
+func (ar *AggregationRunner) Register(
+	name string,
+	source ChangeSource,
+	pipeline mongo.Pipeline,
+	target string,
+	mergeOpts *MergeOptions,
+) {
+	view := InitMaterializedView(source, target, pipeline)
+	view.Merge = mergeOpts
+
+	if view.Merge == nil {
+		view.Merge = &MergeOptions{}
+	}
+
+	ar.mu.Lock()
+	ar.jobs[name] = view
+	ar.mu.Unlock()
+}
+
+// Start runs the pipelines every Interval until ctx is cancelled.
+func (ar *AggregationRunner) Start(ctx context.Context) error {
+	ctx, done, err := beginWorker(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	ticker := time.NewTicker(ar.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := ar.Tick(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			if ar.Leader != nil {
+				ar.Leader.Release(context.Background())
+			}
+
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick runs every registered pipeline once if this instance holds the
+// lead. A failing pipeline does not stop the others.
+func (ar *AggregationRunner) Tick(ctx context.Context) error {
+	if ar.Leader != nil {
+		leader, err := ar.Leader.TryAcquire(ctx)
+
+		if err != nil || !leader {
+			return err
+		}
+	}
+
+	ar.mu.Lock()
+	names := make([]string, 0, len(ar.jobs))
+
+	for name := range ar.jobs {
+		names = append(names, name)
+	}
+
+	ar.mu.Unlock()
+	sort.Strings(names)
+
+	var errs []error
+
+	for _, name := range names {
+		ar.mu.Lock()
+		view := ar.jobs[name]
+		ar.mu.Unlock()
+
+		if err := ar.run(ctx, name, view); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (ar *AggregationRunner) run(ctx context.Context, name string, view *MaterializedView) error {
+	started := time.Now()
+	err := view.Refresh(ctx)
+	metric := Metric{Name: name, Duration: time.Since(started), Err: err}
+
+	if err == nil {
+		database := view.Source.Database()
+
+		if view.Merge.Database != "" {
+			database = database.Client().Database(view.Merge.Database)
+		}
+
+		metric.Count, err = database.Collection(view.Target).EstimatedDocumentCount(ctx)
+	}
+
+	if ar.Metrics != nil {
+		ar.Metrics(metric)
+	}
+
+	return err
+}
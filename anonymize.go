@@ -0,0 +1,176 @@
+package remongo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const anonymizeBatchSize = 500
+
+// Transform computes the replacement of one field value.
+type Transform func(value bson.RawValue) interface{}
+
+// AnonymizeRules maps dotted field paths to the transform applied to them.
+type AnonymizeRules map[string]Transform
+
+// Mask replaces every character of a string but the last keep with '*'.
+// Other values are nulled out.
+func Mask(keep int) Transform {
+	return func(value bson.RawValue) interface{} {
+		s, ok := value.StringValueOK()
+
+		if !ok {
+			return nil
+		}
+
+		runes := []rune(s)
+
+		for i := 0; i < len(runes)-keep; i++ {
+			runes[i] = '*'
+		}
+
+		return string(runes)
+	}
+}
+
+// Hash replaces a value with the hex SHA-256 of salt and the value, so
+// equal inputs stay joinable across collections.
+func Hash(salt string) Transform {
+	return func(value bson.RawValue) interface{} {
+		return hex.EncodeToString(digest(salt, value))
+	}
+}
+
+var (
+	fakeFirstNames = []string{"Alex", "Blake", "Casey", "Dana", "Eli", "Frankie", "Gray", "Harper", "Jordan", "Kai", "Logan", "Morgan", "Noel", "Quinn", "Riley", "Sam"}
+	fakeLastNames  = []string{"Adams", "Brooks", "Carter", "Diaz", "Evans", "Foster", "Garcia", "Hayes", "Ivers", "Jensen", "Kim", "Lopez", "Miller", "Novak", "Olsen", "Patel"}
+)
+
+// FakeName replaces a value with a made-up full name. The same input
+// always yields the same name.
+func FakeName() Transform {
+	return func(value bson.RawValue) interface{} {
+		sum := binary.BigEndian.Uint64(digest("", value))
+
+		first := fakeFirstNames[sum%uint64(len(fakeFirstNames))]
+		last := fakeLastNames[(sum>>32)%uint64(len(fakeLastNames))]
+
+		return first + " " + last
+	}
+}
+
+func NullOut() Transform {
+	return func(bson.RawValue) interface{} {
+		return nil
+	}
+}
+
+func digest(salt string, value bson.RawValue) []byte {
+	hash := sha256.New()
+	hash.Write([]byte(salt))
+
+	if s, ok := value.StringValueOK(); ok {
+		hash.Write([]byte(s))
+	} else {
+		hash.Write(value.Value)
+	}
+
+	return hash.Sum(nil)
+}
+
+// Anonymize rewrites the fields named in rules on every document matching
+// filter, in _id order and anonymizeBatchSize documents per bulk write.
+// It returns the number of documents modified. Absent fields are left
+// absent.
+func (mr *MongoRepository[T]) Anonymize(ctx context.Context, filter interface{}, rules AnonymizeRules) (int64, error) {
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return 0, err
+	}
+
+	projection := bson.M{"_id": 1}
+
+	for path := range rules {
+		projection[path] = 1
+	}
+
+	var modified int64
+	var lastID bson.RawValue
+
+	for {
+		page := query
+
+		if lastID.Type != 0 {
+			page = bson.D{{Key: "$and", Value: bson.A{query, bson.M{"_id": bson.M{"$gt": lastID}}}}}
+		}
+
+		var raws []bson.Raw
+
+		err = mr.runContext(ctx, "Anonymize", page, nil, func(ctx context.Context, op *Operation) error {
+			cursor, err := mr.collection(ctx).Find(
+				ctx,
+				op.Filter,
+				options.Find().
+					SetSort(bson.D{{Key: "_id", Value: 1}}).
+					SetLimit(anonymizeBatchSize).
+					SetProjection(projection),
+			)
+
+			if err != nil {
+				return err
+			}
+
+			if err = cursor.All(ctx, &raws); err != nil {
+				return err
+			}
+
+			writes := []mongo.WriteModel{}
+
+			for _, raw := range raws {
+				set := bson.M{}
+
+				for path, transform := range rules {
+					value, err := raw.LookupErr(strings.Split(path, ".")...)
+
+					if err != nil {
+						continue
+					}
+
+					set[path] = transform(value)
+				}
+
+				if len(set) > 0 {
+					writes = append(writes, mongo.NewUpdateOneModel().
+						SetFilter(bson.M{"_id": raw.Lookup("_id")}).
+						SetUpdate(bson.M{"$set": set}))
+				}
+			}
+
+			if len(writes) == 0 {
+				return nil
+			}
+
+			result, err := mr.collection(ctx).BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+
+			if result != nil {
+				modified += result.ModifiedCount
+			}
+
+			return err
+		})
+
+		if err != nil || len(raws) == 0 {
+			return modified, err
+		}
+
+		lastID = raws[len(raws)-1].Lookup("_id")
+	}
+}
@@ -0,0 +1,44 @@
+package remongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ArchiveMany moves the documents matching filter into archiveCollection,
+// within a transaction where the deployment supports them, see transfer.
+// When archivedAtField is not empty the archived copies are stamped with
+// the archive time under that field.
+func (mr *MongoRepository[T]) ArchiveMany(
+	ctx context.Context,
+	filter interface{},
+	archiveCollection string,
+	archivedAtField string,
+) (int64, error) {
+	now := time.Now()
+
+	// The first find options are the repository defaults alone.
+	find := mr.findOptions(ctx, nil)[0]
+
+	var archived int64
+
+	err := mr.runContext(ctx, "ArchiveMany", filter, nil, func(ctx context.Context, op *Operation) (err error) {
+		archived, err = mr.transfer(ctx, mr.collection(ctx), mr.Database.Collection(archiveCollection), op.Filter, 0, find, func(doc bson.D) bson.D {
+			if archivedAtField != "" {
+				doc = append(doc, bson.E{Key: archivedAtField, Value: now})
+			}
+
+			return doc
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return archived, nil
+}
@@ -0,0 +1,134 @@
+package remongo
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// arrayElement is the identifier the generated arrayFilters bind.
+const arrayElement = "elem"
+
+// UpdateArrayElement updates the elements of arrayField that match
+// elemFilter in every document matching filter. elemFilter is either a
+// condition on element fields, e.g. {"sku": "A1"} or {"qty": {"$lt": 5}},
+// or a scalar for arrays of scalars. update either names element fields
+// to set, e.g. {"qty": 3}, or uses operators, e.g. {"$inc": {"qty": 1}}.
+func (mr *MongoRepository[T]) UpdateArrayElement(
+	ctx context.Context,
+	filter interface{},
+	arrayField string,
+	elemFilter interface{},
+	update interface{},
+) (*UpdateResult, error) {
+	arrayFilter := elementFilter(elemFilter)
+	doc, err := toDocument(update)
+
+	if err != nil {
+		return nil, err
+	}
+
+	path := arrayField + ".$[" + arrayElement + "]"
+	operators := bson.M{}
+
+	for _, e := range doc {
+		if !strings.HasPrefix(e.Key, "$") {
+			set, _ := operators["$set"].(bson.M)
+
+			if set == nil {
+				set = bson.M{}
+				operators["$set"] = set
+			}
+
+			set[path+"."+e.Key] = e.Value
+
+			continue
+		}
+
+		fields, err := toDocument(e.Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		prefixed := bson.M{}
+
+		for _, field := range fields {
+			prefixed[path+"."+field.Key] = field.Value
+		}
+
+		operators[e.Key] = prefixed
+	}
+
+	return mr.updateArray(ctx, filter, operators, arrayFilter)
+}
+
+// ReplaceArrayElement replaces the elements of arrayField that match
+// elemFilter with elem.
+func (mr *MongoRepository[T]) ReplaceArrayElement(
+	ctx context.Context,
+	filter interface{},
+	arrayField string,
+	elemFilter interface{},
+	elem interface{},
+) (*UpdateResult, error) {
+	arrayFilter := elementFilter(elemFilter)
+	update := bson.M{"$set": bson.M{arrayField + ".$[" + arrayElement + "]": elem}}
+
+	return mr.updateArray(ctx, filter, update, arrayFilter)
+}
+
+// RemoveArrayElement pulls the elements of arrayField that match
+// elemFilter.
+func (mr *MongoRepository[T]) RemoveArrayElement(
+	ctx context.Context,
+	filter interface{},
+	arrayField string,
+	elemFilter interface{},
+) (*UpdateResult, error) {
+	err, result := mr.ForRequest(ctx).UpdateManyResult(filter, bson.M{"$pull": bson.M{arrayField: elemFilter}})
+
+	return result, err
+}
+
+func (mr *MongoRepository[T]) updateArray(
+	ctx context.Context,
+	filter interface{},
+	update interface{},
+	arrayFilter interface{},
+) (*UpdateResult, error) {
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{arrayFilter}})
+	err, result := mr.ForRequest(ctx).UpdateManyResult(filter, update, opts)
+
+	return result, err
+}
+
+// elementFilter binds elemFilter to the arrayFilters identifier. Scalars
+// and operator documents such as {"$gt": 5} apply to the element itself.
+func elementFilter(elemFilter interface{}) interface{} {
+	doc, err := toDocument(elemFilter)
+
+	if err != nil || len(doc) == 0 || strings.HasPrefix(doc[0].Key, "$") {
+		return bson.M{arrayElement: elemFilter}
+	}
+
+	filter := bson.D{}
+
+	for _, e := range doc {
+		filter = append(filter, bson.E{Key: arrayElement + "." + e.Key, Value: e.Value})
+	}
+
+	return filter
+}
+
+func toDocument(v interface{}) (bson.D, error) {
+	doc, err := ToBson(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return *doc, nil
+}
@@ -0,0 +1,42 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrForbidden = errors.New("remongo: operation not permitted")
+
+// Authorizer decides whether an operation may run. It sees the operation
+// after every middleware, so it may restrict op.Filter or op.Update, e.g.
+// to the rows of the caller found in ctx, and nothing else can widen them
+// again. It covers FindOne, Find, Query, FindByIDs, FindKeyset, FindRaw,
+// ForEachBatch, Traverse, the aggregation helpers Sample, Buckets,
+// AutoBuckets, SumMoney, GroupByTime and ParallelScan (once per
+// partition), the inserts, replaces, updates and deletes, BulkWrite,
+// UpsertMany, RunCommand, the guarded updates such as TransitionTo and
+// Lease, and the maintenance writes ArchiveMany, Anonymize,
+// RestoreFromTrash, PurgeTrash, RetentionBatch, BulkUpsertStream (once
+// per batch), Backfill, FinalizeRenames, MergeInto and Revert. Compare
+// and Watch, which read the collection directly, are not authorized.
+type Authorizer interface {
+	Authorize(ctx context.Context, op *Operation) error
+}
+
+type AuthorizerFunc func(ctx context.Context, op *Operation) error
+
+func (f AuthorizerFunc) Authorize(ctx context.Context, op *Operation) error {
+	return f(ctx, op)
+}
+
+// Deny returns an error matching ErrForbidden.
+func Deny(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrForbidden, fmt.Sprintf(format, args...))
+}
+
+func WithAuthorizer(authorizer Authorizer) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.authorizer = authorizer
+	}
+}
@@ -0,0 +1,227 @@
+package remongo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// queueSampleInterval throttles serverStatus calls of a Backpressure.
+const queueSampleInterval = 5 * time.Second
+
+// Backpressure sizes the batches and parallel writes of a streaming
+// writer from the feedback of the cluster. A write slower than
+// TargetLatency, a timeout or transient failure, or more than MaxQueue
+// operations queued on the server halves the batch size and drops one
+// concurrent write; healthy writes grow them back by a tenth and one
+// respectively, within MinBatch and MaxBatch, both at least 1. It is safe
+// for concurrent use.
+type Backpressure struct {
+	TargetLatency  time.Duration
+	MinBatch       int
+	MaxBatch       int
+	MaxConcurrency int
+	// Database and MaxQueue enable sampling the server's read and write
+	// queue through serverStatus; zero MaxQueue disables it.
+	Database *mongo.Database
+	MaxQueue int
+
+	mu          sync.Mutex
+	batch       int
+	concurrency int
+	inflight    int
+	wake        chan struct{}
+	queue       int
+	sampled     time.Time
+}
+
+func NewBackpressure(targetLatency time.Duration, maxBatch int, maxConcurrency int) *Backpressure {
+	return &Backpressure{
+		TargetLatency:  targetLatency,
+		MinBatch:       1,
+		MaxBatch:       maxBatch,
+		MaxConcurrency: max(maxConcurrency, 1),
+		batch:          maxBatch,
+		concurrency:    max(maxConcurrency, 1),
+		wake:           make(chan struct{}),
+	}
+}
+
+// BatchSize is the number of documents to send in the next write. A nil
+// Backpressure, or one without MaxBatch, returns fallback.
+func (bp *Backpressure) BatchSize(fallback int) int {
+	if bp == nil {
+		return fallback
+	}
+
+	if bp.MaxBatch <= 0 {
+		return max(fallback, bp.minBatch())
+	}
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	bp.ready()
+
+	return min(max(bp.batch, bp.minBatch()), bp.maxBatch())
+}
+
+// Concurrency is the number of writes allowed in flight. A nil
+// Backpressure allows one.
+func (bp *Backpressure) Concurrency() int {
+	if bp == nil {
+		return 1
+	}
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	bp.ready()
+
+	return bp.concurrency
+}
+
+// ready fills in the state of a Backpressure built as a struct literal.
+// bp.mu must be held.
+func (bp *Backpressure) ready() {
+	if bp.wake == nil {
+		bp.wake = make(chan struct{})
+	}
+
+	if bp.batch <= 0 {
+		bp.batch = bp.maxBatch()
+	}
+
+	if bp.concurrency <= 0 {
+		bp.concurrency = bp.maxConcurrency()
+	}
+}
+
+func (bp *Backpressure) minBatch() int {
+	return max(bp.MinBatch, 1)
+}
+
+func (bp *Backpressure) maxBatch() int {
+	return max(bp.MaxBatch, bp.minBatch())
+}
+
+func (bp *Backpressure) maxConcurrency() int {
+	return max(bp.MaxConcurrency, 1)
+}
+
+// acquire waits until fewer writes than the current concurrency are in
+// flight.
+func (bp *Backpressure) acquire(ctx context.Context) error {
+	if bp == nil {
+		return nil
+	}
+
+	bp.sampleQueue(ctx)
+
+	for {
+		bp.mu.Lock()
+		bp.ready()
+
+		if bp.inflight < bp.concurrency {
+			bp.inflight++
+			bp.mu.Unlock()
+
+			return nil
+		}
+
+		wake := bp.wake
+		bp.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release ends a write acquired before and adapts to how it went.
+func (bp *Backpressure) release(latency time.Duration, err error) {
+	if bp == nil {
+		return
+	}
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	bp.ready()
+	bp.inflight--
+	bp.adapt(latency, err)
+	close(bp.wake)
+	bp.wake = make(chan struct{})
+}
+
+// Observe adapts to a write the caller made on its own.
+func (bp *Backpressure) Observe(ctx context.Context, latency time.Duration, err error) {
+	if bp == nil {
+		return
+	}
+
+	bp.sampleQueue(ctx)
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	bp.ready()
+	bp.adapt(latency, err)
+}
+
+func (bp *Backpressure) adapt(latency time.Duration, err error) {
+	reason := classify(err)
+	pressured := reason == ErrTimeout || reason == ErrTransient ||
+		bp.TargetLatency > 0 && latency > bp.TargetLatency ||
+		bp.MaxQueue > 0 && bp.queue > bp.MaxQueue
+
+	if pressured {
+		bp.batch = max(bp.batch/2, bp.minBatch())
+		bp.concurrency = max(bp.concurrency-1, 1)
+
+		return
+	}
+
+	if err != nil {
+		return
+	}
+
+	bp.batch = min(bp.batch+bp.batch/10+1, bp.maxBatch())
+	bp.concurrency = min(bp.concurrency+1, bp.maxConcurrency())
+}
+
+func (bp *Backpressure) sampleQueue(ctx context.Context) {
+	bp.mu.Lock()
+
+	if bp.Database == nil || bp.MaxQueue <= 0 || time.Since(bp.sampled) < queueSampleInterval {
+		bp.mu.Unlock()
+
+		return
+	}
+
+	bp.sampled = time.Now()
+	bp.mu.Unlock()
+
+	var status struct {
+		GlobalLock struct {
+			CurrentQueue struct {
+				Total int `bson:"total"`
+			} `bson:"currentQueue"`
+		} `bson:"globalLock"`
+	}
+
+	err := bp.Database.RunCommand(ctx, bson.D{{Key: "serverStatus", Value: 1}}).Decode(&status)
+
+	if err != nil {
+		return
+	}
+
+	bp.mu.Lock()
+	bp.queue = status.GlobalLock.CurrentQueue.Total
+	bp.mu.Unlock()
+}
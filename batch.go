@@ -0,0 +1,159 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const CheckpointCollection = "remongo_checkpoints"
+
+type Checkpoint struct {
+	Name       string        `bson:"_id"`
+	Collection string        `bson:"collection"`
+	LastID     bson.RawValue `bson:"last_id,omitempty"`
+	Processed  int64         `bson:"processed"`
+	Done       bool          `bson:"done"`
+	UpdatedAt  time.Time     `bson:"updated_at"`
+}
+
+// WithCheckpoint returns a copy of the repository whose ForEachBatch
+// persists its progress under name and resumes from it after a restart.
+func (mr *MongoRepository[T]) WithCheckpoint(name string) IMongoRepository[T] {
+	clone := *mr
+	clone.checkpoint = name
+
+	return &clone
+}
+
+func (mr *MongoRepository[T]) checkpoints() *mongo.Collection {
+	return mr.Database.Collection(CheckpointCollection)
+}
+
+// ForEachBatch walks the documents matching filter in _id order, handing
+// them to fn batchSize at a time. With a checkpoint, a completed run is
+// not repeated until ResetCheckpoint is called.
+func (mr *MongoRepository[T]) ForEachBatch(
+	ctx context.Context,
+	filter interface{},
+	batchSize int64,
+	fn func([]T) error,
+) error {
+	done, err := beginOperation()
+
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return err
+	}
+
+	checkpoint := Checkpoint{Name: mr.checkpoint, Collection: mr.collection(ctx).Name()}
+
+	if mr.checkpoint != "" {
+		err = mr.checkpoints().FindOne(ctx, bson.M{"_id": mr.checkpoint}).Decode(&checkpoint)
+
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+
+		if checkpoint.Done {
+			return nil
+		}
+	}
+
+	for {
+		page := query
+
+		if checkpoint.LastID.Type != 0 {
+			page = bson.D{{Key: "$and", Value: bson.A{query, bson.M{"_id": bson.M{"$gt": checkpoint.LastID}}}}}
+		}
+
+		started := time.Now()
+
+		var raws []bson.Raw
+
+		err = mr.runContext(ctx, "ForEachBatch", page, nil, func(ctx context.Context, op *Operation) error {
+			cursor, err := mr.collection(ctx).Find(
+				ctx,
+				op.Filter,
+				mr.findOptions(ctx, []*options.FindOptions{
+					options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(batchSize),
+				})...,
+			)
+
+			if err != nil {
+				return err
+			}
+
+			return cursor.All(ctx, &raws)
+		})
+
+		if err != nil {
+			return err
+		}
+
+		mr.RateLimit.Observe(time.Since(started))
+
+		if err = mr.RateLimit.Wait(ctx, len(raws)); err != nil {
+			return err
+		}
+
+		if len(raws) == 0 {
+			checkpoint.Done = true
+
+			return mr.saveCheckpoint(ctx, checkpoint)
+		}
+
+		models := make([]T, len(raws))
+
+		for i, raw := range raws {
+			if err = mr.decode(ctx, raw, &models[i]); err != nil {
+				return err
+			}
+		}
+
+		if err = fn(models); err != nil {
+			return err
+		}
+
+		checkpoint.LastID = raws[len(raws)-1].Lookup("_id")
+		checkpoint.Processed += int64(len(raws))
+
+		if err = mr.saveCheckpoint(ctx, checkpoint); err != nil {
+			return err
+		}
+	}
+}
+
+func (mr *MongoRepository[T]) ResetCheckpoint(ctx context.Context, name string) error {
+	_, err := mr.checkpoints().DeleteOne(ctx, bson.M{"_id": name})
+
+	return err
+}
+
+func (mr *MongoRepository[T]) saveCheckpoint(ctx context.Context, checkpoint Checkpoint) error {
+	if checkpoint.Name == "" {
+		return nil
+	}
+
+	checkpoint.UpdatedAt = time.Now()
+
+	_, err := mr.checkpoints().ReplaceOne(
+		ctx,
+		bson.M{"_id": checkpoint.Name},
+		checkpoint,
+		options.Replace().SetUpsert(true),
+	)
+
+	return err
+}
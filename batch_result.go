@@ -0,0 +1,263 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNotAttempted marks the writes an ordered batch skipped after the
+// first failure.
+var ErrNotAttempted = errors.New("remongo: not attempted after an earlier failure")
+
+// BatchItem is the outcome of the input at Index. Err is classified, so
+// IsDuplicateKey, IsValidation and IsTooLarge apply to it.
+type BatchItem struct {
+	Index int
+	ID    interface{}
+	Err   error
+}
+
+type BatchResult struct {
+	Items []BatchItem
+}
+
+func (br *BatchResult) OK() bool {
+	return len(br.Failed()) == 0
+}
+
+func (br *BatchResult) Failed() []BatchItem {
+	failed := []BatchItem{}
+
+	for _, item := range br.Items {
+		if item.Err != nil {
+			failed = append(failed, item)
+		}
+	}
+
+	return failed
+}
+
+// FailedIndexes lists the inputs to retry.
+func (br *BatchResult) FailedIndexes() []int {
+	indexes := []int{}
+
+	for _, item := range br.Failed() {
+		indexes = append(indexes, item.Index)
+	}
+
+	return indexes
+}
+
+// BatchError is returned when some writes of a batch failed. It unwraps
+// to the individual errors.
+type BatchError struct {
+	Result *BatchResult
+}
+
+func (be *BatchError) Error() string {
+	return fmt.Sprintf("remongo: %d of %d writes failed", len(be.Result.Failed()), len(be.Result.Items))
+}
+
+func (be *BatchError) Unwrap() []error {
+	errs := []error{}
+
+	for _, item := range be.Result.Failed() {
+		errs = append(errs, item.Err)
+	}
+
+	return errs
+}
+
+// newBatchResult maps a bulk write outcome back onto count inputs.
+// sent[i] is the input index of the i-th write that was sent, and ids
+// holds any ids already known per input.
+func newBatchResult(count int, sent []int, ids []interface{}, rejected map[int]error, err error, ordered bool) (*BatchResult, error) {
+	result := &BatchResult{Items: make([]BatchItem, count)}
+
+	for i := range result.Items {
+		result.Items[i] = BatchItem{Index: i, Err: rejected[i]}
+
+		if i < len(ids) && rejected[i] == nil {
+			result.Items[i].ID = ids[i]
+		}
+	}
+
+	var bwe mongo.BulkWriteException
+
+	if err != nil && !errors.As(err, &bwe) {
+		return nil, err
+	}
+
+	firstFailure := -1
+
+	for _, writeErr := range bwe.WriteErrors {
+		if writeErr.Index < 0 || writeErr.Index >= len(sent) {
+			continue
+		}
+
+		item := &result.Items[sent[writeErr.Index]]
+		item.ID, item.Err = nil, translateWriteError(writeErr.WriteError)
+
+		if firstFailure < 0 || writeErr.Index < firstFailure {
+			firstFailure = writeErr.Index
+		}
+	}
+
+	if ordered && firstFailure >= 0 {
+		for _, index := range sent[firstFailure+1:] {
+			result.Items[index].ID, result.Items[index].Err = nil, ErrNotAttempted
+		}
+	}
+
+	if bwe.WriteConcernError != nil && len(bwe.WriteErrors) == 0 {
+		return result, translateError(err)
+	}
+
+	return result, nil
+}
+
+// BulkWrite runs writes and reports the outcome of each of them. The
+// error is only set when the batch could not run at all; per-write
+// failures are in the result.
+func (mr *MongoRepository[T]) BulkWrite(
+	ctx context.Context,
+	writes []mongo.WriteModel,
+	opts ...*options.BulkWriteOptions,
+) (*BatchResult, error) {
+	ordered := true
+	sent := make([]int, len(writes))
+
+	for i := range writes {
+		sent[i] = i
+	}
+
+	for _, opt := range opts {
+		if opt != nil && opt.Ordered != nil {
+			ordered = *opt.Ordered
+		}
+	}
+
+	var bulk *mongo.BulkWriteResult
+
+	err := mr.runContext(ctx, "BulkWrite", nil, nil, func(ctx context.Context, op *Operation) error {
+		return mr.write(ctx, bulkIdempotency(writes), func() (err error) {
+			bulk, err = mr.collection(ctx).BulkWrite(ctx, writes, opts...)
+
+			return err
+		})
+	})
+
+	ids := make([]interface{}, len(writes))
+
+	if bulk != nil {
+		for index, id := range bulk.UpsertedIDs {
+			ids[index] = id
+		}
+	}
+
+	result, err := newBatchResult(len(writes), sent, ids, nil, err, ordered)
+
+	return result, mr.wrapError("BulkWrite", nil, err)
+}
+
+// InsertManyBatch inserts models and reports the outcome of each one.
+// Models rejected before sending, e.g. by validation, are marked failed
+// while the others are still inserted, except in an ordered batch, which
+// stops there and marks the models after it ErrNotAttempted.
+func (mr *MongoRepository[T]) InsertManyBatch(
+	ctx context.Context,
+	models []T,
+	opts ...*options.InsertManyOptions,
+) (*BatchResult, error) {
+	clone := *mr
+	clone.ctx = ctx
+
+	var result *BatchResult
+
+	err := clone.run("InsertMany", nil, nil, func(ctx context.Context, op *Operation) (err error) {
+		result, err = clone.insertBatch(ctx, models, opts)
+
+		return err
+	})
+
+	return result, err
+}
+
+func (mr *MongoRepository[T]) insertBatch(
+	ctx context.Context,
+	models []T,
+	opts []*options.InsertManyOptions,
+) (*BatchResult, error) {
+	ordered := true
+
+	for _, opt := range opts {
+		if opt != nil && opt.Ordered != nil {
+			ordered = *opt.Ordered
+		}
+	}
+
+	docs := []interface{}{}
+	sent := []int{}
+	rejected := map[int]error{}
+
+	for i := range models {
+		mr.assignID(&models[i])
+
+		doc, err := mr.prepareDocument(ctx, &models[i])
+
+		if err != nil {
+			rejected[i] = translateError(err)
+
+			if ordered {
+				for j := i + 1; j < len(models); j++ {
+					rejected[j] = ErrNotAttempted
+				}
+
+				break
+			}
+
+			continue
+		}
+
+		docs = append(docs, doc)
+		sent = append(sent, i)
+	}
+
+	ids := make([]interface{}, len(models))
+
+	if len(docs) == 0 {
+		return newBatchResult(len(models), sent, ids, rejected, nil, ordered)
+	}
+
+	opts = mr.insertManyOptions(ctx, opts)
+
+	var inserted *mongo.InsertManyResult
+
+	// A retried batch reports documents that already landed as duplicate
+	// keys, so it is not treated as an IdempotentInsert.
+	idempotency := Unsafe
+
+	if insertIdempotency(docs...) != Unsafe {
+		idempotency = Idempotent
+	}
+
+	err := mr.write(ctx, idempotency, func() (err error) {
+		inserted, err = mr.collection(ctx).InsertMany(ctx, docs, opts...)
+
+		return err
+	})
+
+	if inserted != nil {
+		for j, id := range inserted.InsertedIDs {
+			if j < len(sent) {
+				ids[sent[j]] = id
+			}
+		}
+	}
+
+	return newBatchResult(len(models), sent, ids, rejected, err, ordered)
+}
@@ -0,0 +1,146 @@
+package remongo
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// benchSampleLimit caps the latencies kept per operation; beyond it a
+// uniform reservoir sample is retained.
+const benchSampleLimit = 10000
+
+type LatencyStats struct {
+	Count  int64
+	Errors int64
+	Min    time.Duration
+	Max    time.Duration
+	Mean   time.Duration
+	P50    time.Duration
+	P95    time.Duration
+	P99    time.Duration
+}
+
+type latencySamples struct {
+	count   int64
+	errors  int64
+	total   time.Duration
+	min     time.Duration
+	max     time.Duration
+	samples []time.Duration
+}
+
+// BenchRepository records the latency of every repository call that runs
+// through the middleware chain, for load tests and canaries that have no
+// metrics pipeline.
+type BenchRepository[T IMongoModel] struct {
+	IMongoRepository[T]
+
+	mu  sync.Mutex
+	ops map[string]*latencySamples
+}
+
+func NewBenchRepository[T IMongoModel](repo *MongoRepository[T]) *BenchRepository[T] {
+	br := &BenchRepository[T]{ops: map[string]*latencySamples{}}
+	br.IMongoRepository = repo.With(WithMiddleware(br.middleware))
+
+	return br
+}
+
+func (br *BenchRepository[T]) middleware(next Handler) Handler {
+	return func(ctx context.Context, op *Operation) error {
+		started := time.Now()
+		err := next(ctx, op)
+		br.record(op.Name, time.Since(started), err)
+
+		return err
+	}
+}
+
+func (br *BenchRepository[T]) record(name string, latency time.Duration, err error) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	s, ok := br.ops[name]
+
+	if !ok {
+		s = &latencySamples{min: latency}
+		br.ops[name] = s
+	}
+
+	s.count++
+	s.total += latency
+
+	if err != nil {
+		s.errors++
+	}
+
+	if latency < s.min {
+		s.min = latency
+	}
+
+	if latency > s.max {
+		s.max = latency
+	}
+
+	if len(s.samples) < benchSampleLimit {
+		s.samples = append(s.samples, latency)
+	} else if i := rand.Int63n(s.count); i < benchSampleLimit {
+		s.samples[i] = latency
+	}
+}
+
+// Report returns the latency distribution of every operation seen so
+// far, keyed by operation name.
+func (br *BenchRepository[T]) Report() map[string]LatencyStats {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	report := make(map[string]LatencyStats, len(br.ops))
+
+	for name, s := range br.ops {
+		sorted := append([]time.Duration(nil), s.samples...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		report[name] = LatencyStats{
+			Count:  s.count,
+			Errors: s.errors,
+			Min:    s.min,
+			Max:    s.max,
+			Mean:   s.total / time.Duration(s.count),
+			P50:    percentile(sorted, 0.50),
+			P95:    percentile(sorted, 0.95),
+			P99:    percentile(sorted, 0.99),
+		}
+	}
+
+	return report
+}
+
+func (br *BenchRepository[T]) Reset() {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	br.ops = map[string]*latencySamples{}
+}
+
+// percentile uses the nearest-rank method on sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(p*float64(len(sorted))+0.5) - 1
+
+	if rank < 0 {
+		rank = 0
+	}
+
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+
+	return sorted[rank]
+}
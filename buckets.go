@@ -0,0 +1,164 @@
+package remongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Bucket counts the documents whose field lies in [Min, Max). Buckets
+// puts documents outside the boundaries in a bucket with nil bounds.
+type Bucket struct {
+	Min   interface{}
+	Max   interface{}
+	Count int64
+}
+
+// Buckets groups the documents matching filter by field into the ranges
+// defined by the sorted boundaries.
+func (mr *MongoRepository[T]) Buckets(
+	ctx context.Context,
+	field string,
+	boundaries []interface{},
+	filter interface{},
+) ([]Bucket, error) {
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	const other = "__other"
+
+	var rows []struct {
+		ID    interface{} `bson:"_id"`
+		Count int64       `bson:"count"`
+	}
+
+	err = mr.aggregateMatching(ctx, "Buckets", query, mongo.Pipeline{
+		{{Key: "$bucket", Value: bson.M{
+			"groupBy":    "$" + field,
+			"boundaries": boundaries,
+			"default":    other,
+			"output":     bson.M{"count": bson.M{"$sum": 1}},
+		}}},
+	}, &rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]Bucket, 0, len(rows))
+
+	for _, row := range rows {
+		if row.ID == other {
+			buckets = append(buckets, Bucket{Count: row.Count})
+
+			continue
+		}
+
+		bucket := Bucket{Min: row.ID, Count: row.Count}
+
+		for i := 0; i < len(boundaries)-1; i++ {
+			if sameValue(boundaries[i], row.ID) {
+				bucket.Max = boundaries[i+1]
+			}
+		}
+
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, nil
+}
+
+// AutoBuckets splits the collection into n buckets of roughly equal
+// size by field.
+func (mr *MongoRepository[T]) AutoBuckets(ctx context.Context, field string, n int) ([]Bucket, error) {
+	var rows []struct {
+		ID struct {
+			Min interface{} `bson:"min"`
+			Max interface{} `bson:"max"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+
+	err := mr.aggregateMatching(ctx, "AutoBuckets", nil, mongo.Pipeline{
+		{{Key: "$bucketAuto", Value: bson.M{"groupBy": "$" + field, "buckets": n}}},
+	}, &rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]Bucket, 0, len(rows))
+
+	for _, row := range rows {
+		buckets = append(buckets, Bucket{Min: row.ID.Min, Max: row.ID.Max, Count: row.Count})
+	}
+
+	return buckets, nil
+}
+
+// aggregateMatching runs pipeline as the named operation over the
+// documents matching filter, as restricted by scopes.
+func (mr *MongoRepository[T]) aggregateMatching(
+	ctx context.Context,
+	name string,
+	filter interface{},
+	pipeline mongo.Pipeline,
+	results interface{},
+) error {
+	return mr.runContext(ctx, name, filter, nil, func(ctx context.Context, op *Operation) error {
+		if op.Filter != nil {
+			pipeline = append(mongo.Pipeline{{{Key: "$match", Value: op.Filter}}}, pipeline...)
+		}
+
+		return mr.aggregateAll(ctx, pipeline, results)
+	})
+}
+
+func (mr *MongoRepository[T]) aggregateAll(ctx context.Context, pipeline mongo.Pipeline, results interface{}) error {
+	cursor, err := mr.collection(ctx).Aggregate(ctx, pipeline, mr.aggregateOptions(ctx, nil)...)
+
+	if err != nil {
+		return err
+	}
+
+	return cursor.All(ctx, results)
+}
+
+// sameValue compares two values as BSON, treating numbers of different
+// types as equal when their values are.
+func sameValue(a interface{}, b interface{}) bool {
+	ta, da, errA := bson.MarshalValue(a)
+	tb, db, errB := bson.MarshalValue(b)
+
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	ra := bson.RawValue{Type: ta, Value: da}
+	rb := bson.RawValue{Type: tb, Value: db}
+
+	if fa, ok := rawNumber(ra); ok {
+		fb, ok := rawNumber(rb)
+
+		return ok && fa == fb
+	}
+
+	return ra.Equal(rb)
+}
+
+func rawNumber(v bson.RawValue) (float64, bool) {
+	switch v.Type {
+	case bson.TypeDouble:
+		return v.Double(), true
+	case bson.TypeInt32:
+		return float64(v.Int32()), true
+	case bson.TypeInt64:
+		return float64(v.Int64()), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,202 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type StreamOptions struct {
+	// BatchSize is the number of documents sent per bulk write.
+	BatchSize int
+	// FlushInterval forces a partial batch to be written after this delay.
+	FlushInterval time.Duration
+	// Backpressure, when set, overrides BatchSize and limits the writes
+	// in flight to fewer than the workers while the cluster is loaded.
+	Backpressure *Backpressure
+}
+
+func mergeStreamOptions(opts ...*StreamOptions) StreamOptions {
+	merged := StreamOptions{BatchSize: 500, FlushInterval: time.Second}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		if opt.BatchSize > 0 {
+			merged.BatchSize = opt.BatchSize
+		}
+
+		if opt.FlushInterval > 0 {
+			merged.FlushInterval = opt.FlushInterval
+		}
+
+		if opt.Backpressure != nil {
+			merged.Backpressure = opt.Backpressure
+		}
+	}
+
+	return merged
+}
+
+// BulkUpsertStream consumes in until it is closed, replacing or inserting
+// each document keyed by keyFields through unordered bulk writes spread
+// over concurrency workers. Workers stop reading while a bulk write is in
+// flight, so a slow cluster pushes back on the producer.
+func (mr *MongoRepository[T]) BulkUpsertStream(
+	ctx context.Context,
+	in <-chan T,
+	keyFields []string,
+	concurrency int,
+	opts ...*StreamOptions,
+) error {
+	done, err := beginOperation()
+
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	config := mergeStreamOptions(opts...)
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := mr.upsertWorker(ctx, in, keyFields, config); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+func (mr *MongoRepository[T]) upsertWorker(
+	ctx context.Context,
+	in <-chan T,
+	keyFields []string,
+	config StreamOptions,
+) error {
+	ticker := time.NewTicker(config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]mongo.WriteModel, 0, config.BatchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := config.Backpressure.acquire(ctx); err != nil {
+			return err
+		}
+
+		started := time.Now()
+		err := mr.runContext(ctx, "BulkUpsertStream", nil, nil, func(ctx context.Context, op *Operation) error {
+			_, err := mr.collection(ctx).
+				BulkWrite(ctx, batch, options.BulkWrite().SetOrdered(false))
+
+			return err
+		})
+		config.Backpressure.release(time.Since(started), err)
+		batch = batch[:0]
+
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		case model, ok := <-in:
+			if !ok {
+				return flush()
+			}
+
+			write, err := mr.upsertModel(ctx, model, keyFields)
+
+			if err != nil {
+				return err
+			}
+
+			batch = append(batch, write)
+
+			if len(batch) >= config.Backpressure.BatchSize(config.BatchSize) {
+				if err = flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (mr *MongoRepository[T]) upsertModel(ctx context.Context, model T, keyFields []string) (mongo.WriteModel, error) {
+	doc, err := mr.prepareDocument(ctx, &model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := keyFilter(doc.(bson.Raw), keyFields)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mongo.NewReplaceOneModel().
+		SetFilter(filter).
+		SetReplacement(doc).
+		SetUpsert(true), nil
+}
+
+// keyFilter builds an equality filter on keyFields from the values of
+// the encoded document, accepting dotted paths into embedded documents.
+// Pass the document as it is sent, so that normalized keys match.
+func keyFilter(raw bson.Raw, keyFields []string) (bson.D, error) {
+	filter := bson.D{}
+
+	for _, field := range keyFields {
+		value, err := raw.LookupErr(strings.Split(field, ".")...)
+
+		if err != nil {
+			return nil, fmt.Errorf("remongo: key field %q missing from document: %w", field, err)
+		}
+
+		filter = append(filter, bson.E{Key: field, Value: value})
+	}
+
+	return filter, nil
+}
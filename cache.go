@@ -0,0 +1,430 @@
+package remongo
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Cache stores encoded query results under tags so that writes can
+// invalidate exactly the entries they may affect.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration, tags []string)
+	InvalidateTags(tags ...string)
+}
+
+type cacheEntry struct {
+	value     []byte
+	tags      []string
+	expiresAt time.Time
+}
+
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Entries   int
+}
+
+// CacheEntryInfo describes a cached entry for introspection.
+type CacheEntryInfo struct {
+	Key       string
+	Namespace string
+	Size      int
+	Tags      []string
+	ExpiresAt time.Time
+}
+
+// MemoryCache is an in-process Cache. It counts hits, misses and
+// evictions per namespace, which is "database.collection" for entries
+// written by repositories.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	tags    map[string]map[string]struct{}
+	stats   map[string]*CacheStats
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: map[string]*cacheEntry{},
+		tags:    map[string]map[string]struct{}{},
+		stats:   map[string]*CacheStats{},
+	}
+}
+
+func (mc *MemoryCache) Stats() map[string]CacheStats {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	stats := make(map[string]CacheStats, len(mc.stats))
+
+	for namespace, s := range mc.stats {
+		stats[namespace] = *s
+	}
+
+	return stats
+}
+
+func (mc *MemoryCache) Inspect(key string) (CacheEntryInfo, bool) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	entry, ok := mc.entries[key]
+
+	if !ok {
+		return CacheEntryInfo{}, false
+	}
+
+	return entry.info(key), true
+}
+
+// Dump lists every entry ordered by key.
+func (mc *MemoryCache) Dump() []CacheEntryInfo {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	keys := make([]string, 0, len(mc.entries))
+
+	for key := range mc.entries {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	infos := make([]CacheEntryInfo, 0, len(keys))
+
+	for _, key := range keys {
+		infos = append(infos, mc.entries[key].info(key))
+	}
+
+	return infos
+}
+
+func (entry *cacheEntry) info(key string) CacheEntryInfo {
+	return CacheEntryInfo{
+		Key:       key,
+		Namespace: cacheNamespace(key),
+		Size:      len(entry.value),
+		Tags:      append([]string(nil), entry.tags...),
+		ExpiresAt: entry.expiresAt,
+	}
+}
+
+// counters must be called with mc.mu held.
+func (mc *MemoryCache) counters(key string) *CacheStats {
+	namespace := cacheNamespace(key)
+
+	if mc.stats[namespace] == nil {
+		mc.stats[namespace] = &CacheStats{}
+	}
+
+	return mc.stats[namespace]
+}
+
+// cacheNamespace extracts "database.collection" from keys built by
+// cacheKey.
+func cacheNamespace(key string) string {
+	parts := strings.SplitN(key, ":", 3)
+
+	if len(parts) < 3 {
+		return ""
+	}
+
+	return parts[0] + "." + parts[1]
+}
+
+func (mc *MemoryCache) Get(key string) ([]byte, bool) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	entry, ok := mc.entries[key]
+
+	if ok && !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		mc.evict(key)
+		ok = false
+	}
+
+	if !ok {
+		mc.counters(key).Misses++
+
+		return nil, false
+	}
+
+	mc.counters(key).Hits++
+
+	return entry.value, true
+}
+
+func (mc *MemoryCache) Set(key string, value []byte, ttl time.Duration, tags []string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.remove(key) {
+		mc.counters(key).Entries--
+	}
+
+	entry := &cacheEntry{value: value, tags: tags}
+
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	mc.entries[key] = entry
+	mc.counters(key).Entries++
+
+	for _, tag := range tags {
+		if mc.tags[tag] == nil {
+			mc.tags[tag] = map[string]struct{}{}
+		}
+
+		mc.tags[tag][key] = struct{}{}
+	}
+}
+
+func (mc *MemoryCache) InvalidateTags(tags ...string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range mc.tags[tag] {
+			mc.evict(key)
+		}
+	}
+}
+
+// evict must be called with mc.mu held.
+func (mc *MemoryCache) evict(key string) {
+	if mc.remove(key) {
+		counters := mc.counters(key)
+		counters.Evictions++
+		counters.Entries--
+	}
+}
+
+// remove must be called with mc.mu held.
+func (mc *MemoryCache) remove(key string) bool {
+	entry, ok := mc.entries[key]
+
+	if !ok {
+		return false
+	}
+
+	delete(mc.entries, key)
+
+	for _, tag := range entry.tags {
+		delete(mc.tags[tag], key)
+
+		if len(mc.tags[tag]) == 0 {
+			delete(mc.tags, tag)
+		}
+	}
+
+	return true
+}
+
+// Every entry carries the collection tag. Cached results are also tagged
+// with the id of each document they contain, and query results with the
+// queries tag, which inserts invalidate. Writes filtered by _id only
+// invalidate the entries containing those ids; an update that makes a
+// document match a cached query it was not part of shows up after
+// CacheTTL.
+func collectionTag(collection string) string {
+	return "collection:" + collection
+}
+
+func queriesTag(collection string) string {
+	return "queries:" + collection
+}
+
+func idTag(collection string, id bson.RawValue) string {
+	return "id:" + collection + ":" + hex.EncodeToString([]byte(idKey(id)))
+}
+
+// cacheKey returns the key for op over filter, or "" when the call cannot
+// be cached because the caller passed options.
+func (mr *MongoRepository[T]) cacheKey(ctx context.Context, op string, filter interface{}, optionCount int) string {
+	if mr.Cache == nil || optionCount > 0 || mr.DryRun {
+		return ""
+	}
+
+	raw, err := bson.Marshal(filter)
+
+	if err != nil {
+		return ""
+	}
+
+	return strings.Join([]string{mr.Database.Name(), mr.collection(ctx).Name(), op, hex.EncodeToString(raw)}, ":")
+}
+
+func (mr *MongoRepository[T]) cacheGet(key string) ([]bson.Raw, bool) {
+	if key == "" {
+		return nil, false
+	}
+
+	value, ok := mr.Cache.Get(key)
+
+	if !ok {
+		return nil, false
+	}
+
+	var cached struct {
+		Docs []bson.Raw `bson:"docs"`
+	}
+
+	if err := bson.Unmarshal(value, &cached); err != nil {
+		return nil, false
+	}
+
+	return cached.Docs, true
+}
+
+// cacheSet stores docs under key. byID marks lookups whose result set
+// cannot change by inserting other documents; misses never are.
+func (mr *MongoRepository[T]) cacheSet(key string, byID bool, docs ...bson.Raw) {
+	if key == "" {
+		return
+	}
+
+	value, err := bson.Marshal(bson.M{"docs": docs})
+
+	if err != nil {
+		return
+	}
+
+	collection := mr.CollectionName()
+	tags := []string{collectionTag(collection)}
+
+	if !byID || len(docs) == 0 {
+		tags = append(tags, queriesTag(collection))
+	}
+
+	for _, doc := range docs {
+		tags = append(tags, idTag(collection, doc.Lookup("_id")))
+	}
+
+	mr.Cache.Set(key, value, mr.CacheTTL, tags)
+}
+
+// invalidateCache drops the entries a successful write may have made
+// stale.
+func (mr *MongoRepository[T]) invalidateCache(op *Operation) {
+	if mr.Cache == nil {
+		return
+	}
+
+	collection := mr.CollectionName()
+
+	if op.Name == "InsertOne" || op.Name == "InsertMany" {
+		mr.Cache.InvalidateTags(queriesTag(collection))
+
+		return
+	}
+
+	ids, ok := filterIDs(op.Filter)
+
+	if !ok {
+		mr.Cache.InvalidateTags(collectionTag(collection))
+
+		return
+	}
+
+	tags := make([]string, 0, len(ids)+1)
+
+	for _, id := range ids {
+		tags = append(tags, idTag(collection, id))
+	}
+
+	if op.Name == "ReplaceOne" || op.Name == "UpdateOne" || op.Name == "UpdateMany" ||
+		op.Name == "RestoreFromTrash" || op.Name == "Revert" {
+		// An upsert inserts a document no cached query contains yet.
+		tags = append(tags, queriesTag(collection))
+	}
+
+	mr.Cache.InvalidateTags(tags...)
+}
+
+// filterIDs returns the ids of a filter of the form {_id: v} or
+// {_id: {$in: [...]}}.
+func filterIDs(filter interface{}) ([]bson.RawValue, bool) {
+	if filter == nil {
+		return nil, false
+	}
+
+	raw, err := bson.Marshal(filter)
+
+	if err != nil {
+		return nil, false
+	}
+
+	elements, err := bson.Raw(raw).Elements()
+
+	if err != nil || len(elements) != 1 || elements[0].Key() != "_id" {
+		return nil, false
+	}
+
+	value := elements[0].Value()
+	doc, isDoc := value.DocumentOK()
+
+	if !isDoc {
+		return []bson.RawValue{value}, true
+	}
+
+	operators, err := doc.Elements()
+
+	if err != nil || len(operators) != 1 {
+		return nil, false
+	}
+
+	switch operators[0].Key() {
+	case "$eq":
+		return []bson.RawValue{operators[0].Value()}, true
+	case "$in":
+		array, ok := operators[0].Value().ArrayOK()
+
+		if !ok {
+			return nil, false
+		}
+
+		values, err := array.Values()
+
+		return values, err == nil
+	}
+
+	if strings.HasPrefix(operators[0].Key(), "$") {
+		return nil, false
+	}
+
+	return []bson.RawValue{value}, true
+}
+
+func (mr *MongoRepository[T]) decodeAll(ctx context.Context, raws []bson.Raw) ([]T, error) {
+	models := make([]T, len(raws))
+
+	for i, raw := range raws {
+		if err := mr.decode(ctx, raw, &models[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return models, nil
+}
+
+// CacheStats returns the statistics of the repository collection when
+// the cache keeps them.
+func (mr *MongoRepository[T]) CacheStats() (CacheStats, bool) {
+	cache, ok := mr.Cache.(interface{ Stats() map[string]CacheStats })
+
+	if !ok {
+		return CacheStats{}, false
+	}
+
+	stats, ok := cache.Stats()[mr.Database.Name()+"."+mr.CollectionName()]
+
+	return stats, ok
+}
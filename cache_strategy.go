@@ -0,0 +1,346 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CacheStrategy int
+
+const (
+	// CacheInvalidate drops affected entries on every write.
+	CacheInvalidate CacheStrategy = iota
+	// CacheWriteThrough also stores inserted and replaced documents so
+	// the next lookup by _id is served from the cache.
+	CacheWriteThrough
+	// CacheWriteBehind caches inserted and replaced documents right away
+	// and writes them later as upserts, keeping only the last write per
+	// _id. Writes without an _id, and replacements that are not upserts
+	// filtered by a single _id, stay synchronous. A deferred replacement
+	// reports one modified document. Writes whose flush timed out, failed
+	// transiently or hit a failover are retried on the next one; other
+	// failures are logged and dropped.
+	CacheWriteBehind
+)
+
+type pendingWrite struct {
+	collection *mongo.Collection
+	id         bson.RawValue
+	doc        bson.Raw
+}
+
+// writeBehind coalesces deferred writes until they are flushed.
+type writeBehind struct {
+	interval time.Duration
+	// flush returns the writes to try again.
+	flush func(ctx context.Context, writes []pendingWrite) ([]pendingWrite, error)
+
+	mu      sync.Mutex
+	pending map[string]pendingWrite
+	order   []string
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func newWriteBehind(
+	interval time.Duration,
+	flush func(ctx context.Context, writes []pendingWrite) ([]pendingWrite, error),
+) *writeBehind {
+	wb := &writeBehind{
+		interval: interval,
+		flush:    flush,
+		pending:  map[string]pendingWrite{},
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go wb.loop()
+
+	return wb
+}
+
+func (pw pendingWrite) key() string {
+	return pw.collection.Name() + ":" + idKey(pw.id)
+}
+
+func (wb *writeBehind) add(write pendingWrite) {
+	key := write.key()
+
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	if _, ok := wb.pending[key]; !ok {
+		wb.order = append(wb.order, key)
+	}
+
+	wb.pending[key] = write
+}
+
+func (wb *writeBehind) take() []pendingWrite {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	writes := make([]pendingWrite, 0, len(wb.order))
+
+	for _, key := range wb.order {
+		writes = append(writes, wb.pending[key])
+	}
+
+	wb.pending = map[string]pendingWrite{}
+	wb.order = nil
+
+	return writes
+}
+
+// requeue puts back writes whose flush failed, unless a newer write for
+// the same _id was queued meanwhile.
+func (wb *writeBehind) requeue(writes []pendingWrite) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	order := []string{}
+
+	for _, write := range writes {
+		key := write.key()
+
+		if _, ok := wb.pending[key]; ok {
+			continue
+		}
+
+		wb.pending[key] = write
+		order = append(order, key)
+	}
+
+	wb.order = append(order, wb.order...)
+}
+
+func (wb *writeBehind) Flush(ctx context.Context) error {
+	writes := wb.take()
+
+	if len(writes) == 0 {
+		return nil
+	}
+
+	retry, err := wb.flush(ctx, writes)
+	wb.requeue(retry)
+
+	return err
+}
+
+func (wb *writeBehind) loop() {
+	defer close(wb.done)
+
+	ticker := time.NewTicker(wb.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-wb.stop:
+			return
+		case <-ticker.C:
+			wb.Flush(context.Background())
+		}
+	}
+}
+
+// Close stops the background flush and writes what is still pending.
+func (wb *writeBehind) Close(ctx context.Context) error {
+	select {
+	case <-wb.stop:
+	default:
+		close(wb.stop)
+	}
+
+	<-wb.done
+
+	return wb.Flush(ctx)
+}
+
+func WithCacheStrategy(strategy CacheStrategy) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.cacheStrategy = strategy
+	}
+}
+
+// WithWriteBehind selects CacheWriteBehind, flushing every interval.
+func WithWriteBehind(interval time.Duration) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.cacheStrategy = CacheWriteBehind
+		c.flushInterval = interval
+	}
+}
+
+// FlushWrites writes the pending write-behind documents now.
+func (mr *MongoRepository[T]) FlushWrites(ctx context.Context) error {
+	if mr.writeBehind == nil {
+		return nil
+	}
+
+	return mr.writeBehind.Flush(ctx)
+}
+
+// flushWrites upserts writes and returns those that failed with an
+// error worth retrying.
+func (mr *MongoRepository[T]) flushWrites(ctx context.Context, writes []pendingWrite) ([]pendingWrite, error) {
+	byCollection := map[*mongo.Collection][]pendingWrite{}
+	collections := []*mongo.Collection{}
+
+	for _, write := range writes {
+		if _, ok := byCollection[write.collection]; !ok {
+			collections = append(collections, write.collection)
+		}
+
+		byCollection[write.collection] = append(byCollection[write.collection], write)
+	}
+
+	var retry []pendingWrite
+	var firstErr error
+
+	for _, collection := range collections {
+		batch := byCollection[collection]
+		models := make([]mongo.WriteModel, 0, len(batch))
+
+		for _, write := range batch {
+			models = append(models, mongo.NewReplaceOneModel().
+				SetFilter(bson.D{{Key: "_id", Value: write.id}}).
+				SetReplacement(write.doc).
+				SetUpsert(true))
+		}
+
+		err := mr.write(ctx, Idempotent, func() error {
+			_, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+
+			return err
+		})
+
+		if err == nil {
+			continue
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+
+		var bwe mongo.BulkWriteException
+
+		if !errors.As(err, &bwe) || len(bwe.WriteErrors) == 0 {
+			// Nothing tells which documents landed; a write concern error
+			// means they all did.
+			if retryableFlush(err) && bwe.WriteConcernError == nil {
+				retry = append(retry, batch...)
+			} else {
+				mr.logger().Printf("remongo: write-behind flush to %s dropped %d document(s): %v", collection.Name(), len(batch), err)
+			}
+
+			continue
+		}
+
+		for _, writeErr := range bwe.WriteErrors {
+			write := batch[writeErr.Index]
+
+			if err := translateWriteError(writeErr.WriteError); retryableFlush(err) {
+				retry = append(retry, write)
+			} else {
+				mr.logger().Printf("remongo: write-behind flush to %s dropped _id %s: %v", collection.Name(), write.id, err)
+			}
+		}
+	}
+
+	return retry, firstErr
+}
+
+func retryableFlush(err error) bool {
+	return IsTransient(err) || IsTimeout(err) || isFailover(err)
+}
+
+// deferWrite queues doc when write-behind applies to it and returns its
+// _id.
+func (mr *MongoRepository[T]) deferWrite(
+	op *Operation,
+	collection *mongo.Collection,
+	doc interface{},
+) (interface{}, bool, error) {
+	if mr.writeBehind == nil || mr.DryRun {
+		return nil, false, nil
+	}
+
+	raw, err := mr.marshal(doc)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	id, err := raw.LookupErr("_id")
+
+	if op.Name == "ReplaceOne" {
+		ids, ok := filterIDs(op.Filter)
+
+		if !ok || len(ids) != 1 {
+			return nil, false, nil
+		}
+
+		id, err = ids[0], nil
+	}
+
+	if err != nil {
+		return nil, false, nil
+	}
+
+	mr.writeBehind.add(pendingWrite{collection: collection, id: id, doc: raw})
+	op.written = raw
+
+	var value interface{}
+
+	if err = id.Unmarshal(&value); err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+// cacheWritten stores the document written by op after the usual
+// invalidation, for the write-through and write-behind strategies.
+func (mr *MongoRepository[T]) cacheWritten(ctx context.Context, op *Operation) {
+	if mr.Cache == nil || mr.CacheStrategy == CacheInvalidate || op.written == nil {
+		return
+	}
+
+	id, err := op.written.LookupErr("_id")
+
+	if err != nil {
+		return
+	}
+
+	mr.cacheSet(mr.cacheKey(ctx, "FindOne", bson.D{{Key: "_id", Value: id}}, 0), true, op.written)
+}
+
+// trackWritten records doc for cacheWritten when a strategy needs it.
+func (mr *MongoRepository[T]) trackWritten(op *Operation, doc interface{}) {
+	if mr.Cache == nil || mr.CacheStrategy == CacheInvalidate {
+		return
+	}
+
+	if raw, err := mr.marshal(doc); err == nil {
+		op.written = raw
+	}
+}
+
+// replaceUpserts reports whether opts ask for an upsert, the last one
+// setting it winning as in the driver.
+func replaceUpserts(opts []*options.ReplaceOptions) bool {
+	upsert := false
+
+	for _, opt := range opts {
+		if opt != nil && opt.Upsert != nil {
+			upsert = *opt.Upsert
+		}
+	}
+
+	return upsert
+}
@@ -0,0 +1,48 @@
+package remongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestWriteBehindRequeuesOnlyRetryableFailures(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("partial failure", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "writeErrors", Value: bson.A{
+				bson.D{{Key: "index", Value: 0}, {Key: "code", Value: 11000}, {Key: "errmsg", Value: "E11000 duplicate key"}},
+				bson.D{{Key: "index", Value: 1}, {Key: "code", Value: 189}, {Key: "errmsg", Value: "primary stepped down"}},
+			}},
+		))
+
+		repo := NewRepository[transitionOrder](mt.DB, WithWriteBehind(time.Hour)).(*MongoRepository[transitionOrder])
+		defer repo.writeBehind.Close(context.Background())
+
+		for id := int32(1); id <= 3; id++ {
+			raw, err := bson.Marshal(bson.D{{Key: "_id", Value: id}})
+
+			if err != nil {
+				mt.Fatal(err)
+			}
+
+			doc := bson.Raw(raw)
+			repo.writeBehind.add(pendingWrite{collection: mt.Coll, id: doc.Lookup("_id"), doc: doc})
+		}
+
+		if err := repo.FlushWrites(context.Background()); err == nil {
+			mt.Fatal("want the flush error")
+		}
+
+		pending := repo.writeBehind.take()
+
+		if len(pending) != 1 || pending[0].id.Int32() != 2 {
+			mt.Fatalf("want only _id 2 requeued, got %v", pending)
+		}
+	})
+}
@@ -0,0 +1,283 @@
+package remongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ConsumerCollection   = "remongo_consumers"
+	DeadLetterCollection = "remongo_dead_letters"
+)
+
+// BatchHandler processes a batch of change events. Batches may be
+// delivered again after a failure or a restart.
+type BatchHandler func(ctx context.Context, events []ChangeEvent) error
+
+// Watcher is satisfied by every repository.
+type Watcher interface {
+	Watch(ctx context.Context, handler ChangeHandler, opts ...*WatchOptions) error
+}
+
+type ConsumerOptions struct {
+	// BatchSize and FlushInterval bound how long events are buffered.
+	BatchSize     int
+	FlushInterval time.Duration
+	// MaxAttempts is how often a batch, and then each of its events on
+	// its own, is tried before the failing events are dead-lettered.
+	MaxAttempts int
+	// Backoff is the pause after the first failure; it doubles after
+	// each further one.
+	Backoff time.Duration
+	// DeadLetter names the collection receiving poison events.
+	DeadLetter   string
+	OnInvalidate InvalidatePolicy
+	Resync       func(ctx context.Context) error
+	// Backpressure, when set, overrides BatchSize from how long the
+	// handler takes and how it fails.
+	Backpressure *Backpressure
+}
+
+func mergeConsumerOptions(opts ...*ConsumerOptions) ConsumerOptions {
+	merged := ConsumerOptions{
+		BatchSize:     100,
+		FlushInterval: time.Second,
+		MaxAttempts:   3,
+		Backoff:       100 * time.Millisecond,
+		DeadLetter:    DeadLetterCollection,
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		if opt.BatchSize > 0 {
+			merged.BatchSize = opt.BatchSize
+		}
+
+		if opt.FlushInterval > 0 {
+			merged.FlushInterval = opt.FlushInterval
+		}
+
+		if opt.MaxAttempts > 0 {
+			merged.MaxAttempts = opt.MaxAttempts
+		}
+
+		if opt.Backoff > 0 {
+			merged.Backoff = opt.Backoff
+		}
+
+		if opt.DeadLetter != "" {
+			merged.DeadLetter = opt.DeadLetter
+		}
+
+		merged.OnInvalidate = opt.OnInvalidate
+		merged.Resync = opt.Resync
+		merged.Backpressure = opt.Backpressure
+	}
+
+	return merged
+}
+
+type DeadLetter struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	Consumer string             `bson:"consumer"`
+	Event    ChangeEvent        `bson:"event"`
+	Error    string             `bson:"error"`
+	Attempts int                `bson:"attempts"`
+	FailedAt time.Time          `bson:"failed_at"`
+}
+
+// ChangeConsumer feeds the change stream of Source to a BatchHandler with
+// at-least-once delivery: the resume position is stored in
+// ConsumerCollection under Name only after a batch is handled. A failing
+// batch is retried with backoff, then split to find the events that keep
+// failing, which are moved to the dead-letter collection so the rest of
+// the stream can proceed.
+type ChangeConsumer struct {
+	Database *mongo.Database
+	Name     string
+	Source   Watcher
+	Options  ConsumerOptions
+}
+
+func InitChangeConsumer(database *mongo.Database, name string, source Watcher, opts ...*ConsumerOptions) *ChangeConsumer {
+	return &ChangeConsumer{
+		Database: database,
+		Name:     name,
+		Source:   source,
+		Options:  mergeConsumerOptions(opts...),
+	}
+}
+
+// Run consumes until ctx is cancelled or storing progress fails.
+func (c *ChangeConsumer) Run(ctx context.Context, handler BatchHandler) error {
+	token, err := c.loadToken(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events := make(chan ChangeEvent)
+	watched := make(chan error, 1)
+
+	go func() {
+		watched <- c.Source.Watch(ctx, func(ctx context.Context, event ChangeEvent) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}, &WatchOptions{
+			ResumeAfter:  token,
+			OnInvalidate: c.Options.OnInvalidate,
+			Resync:       c.Options.Resync,
+		})
+	}()
+
+	ticker := time.NewTicker(c.Options.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]ChangeEvent, 0, c.Options.BatchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		started := time.Now()
+		err := c.process(ctx, handler, batch)
+		c.Options.Backpressure.Observe(ctx, time.Since(started), err)
+
+		if err != nil {
+			return err
+		}
+
+		if err := c.saveToken(ctx, batch[len(batch)-1].ID); err != nil {
+			return err
+		}
+
+		batch = batch[:0]
+
+		return nil
+	}
+
+	for {
+		select {
+		case event := <-events:
+			batch = append(batch, event)
+
+			if len(batch) >= c.Options.Backpressure.BatchSize(c.Options.BatchSize) {
+				err = flush()
+			}
+		case <-ticker.C:
+			err = flush()
+		case err = <-watched:
+			if err == nil || ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			return err
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			return err
+		}
+	}
+}
+
+func (c *ChangeConsumer) process(ctx context.Context, handler BatchHandler, batch []ChangeEvent) error {
+	if c.attempt(ctx, handler, batch) == nil {
+		return nil
+	}
+
+	for i := range batch {
+		event := batch[i : i+1]
+
+		if err := c.attempt(ctx, handler, event); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if err = c.deadLetter(ctx, event[0], err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *ChangeConsumer) attempt(ctx context.Context, handler BatchHandler, events []ChangeEvent) error {
+	backoff := c.Options.Backoff
+	var err error
+
+	for attempt := 0; attempt < c.Options.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if err = handler(ctx, events); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+func (c *ChangeConsumer) deadLetter(ctx context.Context, event ChangeEvent, cause error) error {
+	_, err := c.Database.Collection(c.Options.DeadLetter).InsertOne(ctx, DeadLetter{
+		ID:       primitive.NewObjectID(),
+		Consumer: c.Name,
+		Event:    event,
+		Error:    cause.Error(),
+		Attempts: c.Options.MaxAttempts,
+		FailedAt: time.Now(),
+	})
+
+	return err
+}
+
+func (c *ChangeConsumer) loadToken(ctx context.Context) (bson.Raw, error) {
+	var state struct {
+		Token bson.Raw `bson:"token"`
+	}
+
+	err := c.Database.Collection(ConsumerCollection).FindOne(ctx, bson.M{"_id": c.Name}).Decode(&state)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+
+	return state.Token, err
+}
+
+func (c *ChangeConsumer) saveToken(ctx context.Context, token bson.Raw) error {
+	_, err := c.Database.Collection(ConsumerCollection).UpdateOne(
+		ctx,
+		bson.M{"_id": c.Name},
+		bson.M{"$set": bson.M{"token": token, "updated_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+
+	return err
+}
@@ -0,0 +1,109 @@
+package remongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ChangeEvent is a decoded change stream event.
+type ChangeEvent struct {
+	ID            bson.Raw        `bson:"_id"`
+	OperationType string          `bson:"operationType"`
+	Namespace     ChangeNamespace `bson:"ns"`
+	DocumentKey   bson.Raw        `bson:"documentKey"`
+	FullDocument  bson.Raw        `bson:"fullDocument,omitempty"`
+	// FullDocumentBeforeChange is only set by streams asking for
+	// pre-images.
+	FullDocumentBeforeChange bson.Raw            `bson:"fullDocumentBeforeChange,omitempty"`
+	UpdateDescription        *UpdateDescription  `bson:"updateDescription,omitempty"`
+	ClusterTime              primitive.Timestamp `bson:"clusterTime"`
+}
+
+type ChangeNamespace struct {
+	Database   string `bson:"db"`
+	Collection string `bson:"coll"`
+}
+
+type UpdateDescription struct {
+	UpdatedFields bson.Raw `bson:"updatedFields"`
+	RemovedFields []string `bson:"removedFields"`
+}
+
+// ChangeSource is anything that exposes the collection to watch; every
+// repository satisfies it.
+type ChangeSource interface {
+	GetCollection() *mongo.Collection
+}
+
+// ChangeHandler receives the events of Watch.
+type ChangeHandler func(ctx context.Context, event ChangeEvent) error
+
+// followStream hands the events of source to apply one at a time,
+// storing the resume position under name in ConsumerCollection after
+// each, until ctx is cancelled.
+func followStream(
+	ctx context.Context,
+	source *mongo.Collection,
+	name string,
+	pipeline mongo.Pipeline,
+	opts *options.ChangeStreamOptions,
+	apply ChangeHandler,
+) error {
+	positions := source.Database().Collection(ConsumerCollection)
+
+	var state struct {
+		Token bson.Raw `bson:"token"`
+	}
+
+	err := positions.FindOne(ctx, bson.M{"_id": name}).Decode(&state)
+
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+
+	if state.Token != nil {
+		opts.SetResumeAfter(state.Token)
+	}
+
+	stream, err := source.Watch(ctx, pipeline, opts)
+
+	if err != nil {
+		return err
+	}
+
+	defer stream.Close(ctx)
+
+	for stream.Next(ctx) {
+		var event ChangeEvent
+
+		if err = stream.Decode(&event); err != nil {
+			return err
+		}
+
+		if err = apply(ctx, event); err != nil {
+			return err
+		}
+
+		_, err = positions.UpdateOne(
+			ctx,
+			bson.M{"_id": name},
+			bson.M{"$set": bson.M{"token": stream.ResumeToken(), "updated_at": time.Now()}},
+			options.Update().SetUpsert(true),
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return stream.Err()
+}
@@ -0,0 +1,74 @@
+package remongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CollectionChanges lists what ModifyCollection changes; zero fields are
+// left as they are.
+type CollectionChanges struct {
+	// Validator replaces the validator, e.g. {"$jsonSchema": ...}.
+	Validator interface{}
+	// ValidationLevel is "off", "strict" or "moderate".
+	ValidationLevel string
+	// ValidationAction is "error" or "warn".
+	ValidationAction string
+	TTL              []TTLChange
+}
+
+// TTLChange sets the expiry of an existing TTL index, found by Name or,
+// when Name is empty, by Keys.
+type TTLChange struct {
+	Name        string
+	Keys        bson.D
+	ExpireAfter time.Duration
+}
+
+// ModifyCollection applies changes with collMod. The server accepts one
+// index per command, so TTL changes are sent after the validation
+// changes, one at a time, and the first failure stops the rest.
+func (mr *MongoRepository[T]) ModifyCollection(ctx context.Context, changes CollectionChanges) error {
+	collection := mr.collection(ctx).Name()
+	cmd := bson.D{{Key: "collMod", Value: collection}}
+
+	if changes.Validator != nil {
+		cmd = append(cmd, bson.E{Key: "validator", Value: changes.Validator})
+	}
+
+	if changes.ValidationLevel != "" {
+		cmd = append(cmd, bson.E{Key: "validationLevel", Value: changes.ValidationLevel})
+	}
+
+	if changes.ValidationAction != "" {
+		cmd = append(cmd, bson.E{Key: "validationAction", Value: changes.ValidationAction})
+	}
+
+	if len(cmd) > 1 {
+		if _, err := mr.Command(ctx, cmd); err != nil {
+			return err
+		}
+	}
+
+	for _, change := range changes.TTL {
+		index := bson.D{}
+
+		if change.Name != "" {
+			index = append(index, bson.E{Key: "name", Value: change.Name})
+		} else {
+			index = append(index, bson.E{Key: "keyPattern", Value: change.Keys})
+		}
+
+		index = append(index, bson.E{Key: "expireAfterSeconds", Value: int64(change.ExpireAfter.Seconds())})
+
+		_, err := mr.Command(ctx, bson.D{{Key: "collMod", Value: collection}, {Key: "index", Value: index}})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
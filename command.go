@@ -0,0 +1,54 @@
+package remongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Commander runs database commands; both repositories and Admin do.
+type Commander interface {
+	Command(ctx context.Context, cmd interface{}, opts ...*options.RunCmdOptions) (bson.Raw, error)
+}
+
+// RunCommand runs cmd, which must be an ordered document such as bson.D,
+// and decodes the reply into R, e.g.
+//
+//	stats, err := remongo.RunCommand[CollStats](ctx, repo, bson.D{{Key: "collStats", Value: "users"}})
+func RunCommand[R any](ctx context.Context, target Commander, cmd interface{}, opts ...*options.RunCmdOptions) (R, error) {
+	var reply R
+
+	raw, err := target.Command(ctx, cmd, opts...)
+
+	if err != nil {
+		return reply, err
+	}
+
+	return reply, bson.Unmarshal(raw, &reply)
+}
+
+// Command runs cmd on the repository database as the "RunCommand"
+// operation, so the timeout, middleware, hooks and error translation of
+// the repository apply. Commands may change data, so the collection's
+// cache entries are dropped afterwards.
+func (mr *MongoRepository[T]) Command(ctx context.Context, cmd interface{}, opts ...*options.RunCmdOptions) (bson.Raw, error) {
+	clone := *mr
+	clone.ctx = ctx
+
+	var reply bson.Raw
+
+	err := clone.run("RunCommand", nil, cmd, func(ctx context.Context, op *Operation) (err error) {
+		reply, err = mr.Database.RunCommand(ctx, op.Update, opts...).Raw()
+
+		return translateError(err)
+	})
+
+	return reply, err
+}
+
+func (a *Admin) Command(ctx context.Context, cmd interface{}, opts ...*options.RunCmdOptions) (bson.Raw, error) {
+	reply, err := a.GetDB().RunCommand(ctx, cmd, opts...).Raw()
+
+	return reply, translateError(err)
+}
@@ -0,0 +1,62 @@
+package remongo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+const packagePath = "github.com/oneapplab/remongo."
+
+// queryComment builds the $comment attached to every operation so slow
+// queries in the profiler and currentOp can be traced back to the caller.
+func (mr *MongoRepository[T]) queryComment(ctx context.Context) string {
+	if !mr.TagQueries {
+		return ""
+	}
+
+	tag := map[string]string{"caller": callSite()}
+
+	if mr.ServiceName != "" {
+		tag["service"] = mr.ServiceName
+	}
+
+	if mr.queryName != "" {
+		tag["query"] = mr.queryName
+	}
+
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		tag["request_id"] = requestID
+	}
+
+	if actor := ActorFromContext(ctx); actor != "" {
+		tag["actor"] = actor
+	}
+
+	comment, err := json.Marshal(tag)
+
+	if err != nil {
+		return ""
+	}
+
+	return string(comment)
+}
+
+func callSite() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	for {
+		frame, more := frames.Next()
+
+		if !strings.HasPrefix(frame.Function, packagePath) {
+			return fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line)
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package remongo
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// compareBatchSize is how many documents Compare looks up at once.
+const compareBatchSize = 500
+
+// DocumentDiff lists the fields of a document that differ, Before being
+// the repository's value and After the other's.
+type DocumentDiff struct {
+	Key    bson.D
+	Fields []FieldChange
+}
+
+// CompareReport is the outcome of Compare. Missing lists keys only the
+// repository has and Extra keys only the other one has.
+type CompareReport struct {
+	Matched   int64
+	Missing   []bson.D
+	Extra     []bson.D
+	Differing []DocumentDiff
+}
+
+func (cr *CompareReport) Consistent() bool {
+	return len(cr.Missing) == 0 && len(cr.Extra) == 0 && len(cr.Differing) == 0
+}
+
+// Compare streams the documents matching filter from the repository and
+// from other, pairs them on keyFields (the _id by default) and reports
+// those missing on either side and those whose fields differ, to verify
+// migrations, replications and backfills. Numbers compare by value
+// whatever their bson type.
+func (mr *MongoRepository[T]) Compare(
+	ctx context.Context,
+	other IMongoRepository[T],
+	filter interface{},
+	keyFields ...string,
+) (*CompareReport, error) {
+	if len(keyFields) == 0 {
+		keyFields = []string{"_id"}
+	}
+
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CompareReport{Missing: []bson.D{}, Extra: []bson.D{}, Differing: []DocumentDiff{}}
+	left, right := mr.collection(ctx), other.GetCollection()
+
+	err = compareStream(ctx, left, right, query, keyFields, func(key bson.D, doc bson.Raw, match bson.Raw) {
+		if match == nil {
+			report.Missing = append(report.Missing, key)
+
+			return
+		}
+
+		diffs := diffRaw(doc, match)
+
+		if len(diffs) > 0 {
+			report.Differing = append(report.Differing, DocumentDiff{Key: key, Fields: diffs})
+		} else {
+			report.Matched++
+		}
+	})
+
+	if err != nil {
+		return nil, mr.wrapError("Compare", filter, err)
+	}
+
+	err = compareStream(ctx, right, left, query, keyFields, func(key bson.D, doc bson.Raw, match bson.Raw) {
+		if match == nil {
+			report.Extra = append(report.Extra, key)
+		}
+	})
+
+	if err != nil {
+		return nil, mr.wrapError("Compare", filter, err)
+	}
+
+	return report, nil
+}
+
+// compareStream walks from in batches and calls visit with each of its
+// documents and the document of to with the same key, or nil.
+func compareStream(
+	ctx context.Context,
+	from *mongo.Collection,
+	to *mongo.Collection,
+	query interface{},
+	keyFields []string,
+	visit func(key bson.D, doc bson.Raw, match bson.Raw),
+) error {
+	cursor, err := from.Find(ctx, query, options.Find().SetBatchSize(compareBatchSize))
+
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(ctx)
+
+	batch := []bson.Raw{}
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		keys := make([]bson.D, len(batch))
+		clauses := make(bson.A, len(batch))
+
+		for i, doc := range batch {
+			keys[i] = compareKey(doc, keyFields)
+			clauses[i] = keys[i]
+		}
+
+		matches, err := to.Find(ctx, bson.M{"$or": clauses})
+
+		if err != nil {
+			return err
+		}
+
+		byKey := map[string]bson.Raw{}
+
+		for matches.Next(ctx) {
+			doc := append(bson.Raw(nil), matches.Current...)
+			byKey[keyString(compareKey(doc, keyFields))] = doc
+		}
+
+		matches.Close(ctx)
+
+		if err = matches.Err(); err != nil {
+			return err
+		}
+
+		for i, doc := range batch {
+			visit(keys[i], doc, byKey[keyString(keys[i])])
+		}
+
+		batch = batch[:0]
+
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		batch = append(batch, append(bson.Raw(nil), cursor.Current...))
+
+		if len(batch) == compareBatchSize {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = cursor.Err(); err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// compareKey returns the key fields of doc. A missing field is keyed as
+// null, which matches documents lacking it too.
+func compareKey(doc bson.Raw, keyFields []string) bson.D {
+	key := make(bson.D, 0, len(keyFields))
+
+	for _, field := range keyFields {
+		value, err := doc.LookupErr(strings.Split(field, ".")...)
+
+		if err != nil {
+			key = append(key, bson.E{Key: field, Value: nil})
+
+			continue
+		}
+
+		key = append(key, bson.E{Key: field, Value: value})
+	}
+
+	return key
+}
+
+func keyString(key bson.D) string {
+	raw, _ := bson.Marshal(key)
+
+	return string(raw)
+}
+
+func diffRaw(left bson.Raw, right bson.Raw) []FieldChange {
+	before, _ := canonical(bson.RawValue{Type: bson.TypeEmbeddedDocument, Value: left}).(bson.M)
+	after, _ := canonical(bson.RawValue{Type: bson.TypeEmbeddedDocument, Value: right}).(bson.M)
+	changes := diffDocuments("", before, after)
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
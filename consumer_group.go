@@ -0,0 +1,395 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const ConsumerGroupCollection = "remongo_consumer_groups"
+
+var errPartitionLost = errors.New("remongo: partition lease lost")
+
+// tokenSaveInterval throttles resume token writes for events that belong
+// to other partitions.
+const tokenSaveInterval = time.Second
+
+// ConsumerGroup shares the change stream of one collection between the
+// instances running it under the same Name. Events are split into
+// Partitions by a hash of their document key; every partition is owned
+// by one live member at a time, through a lease that also stores its
+// resume token, and partitions are spread evenly over the members that
+// are heartbeating. A partition changing hands may see events again, so
+// handlers must be idempotent.
+type ConsumerGroup struct {
+	Database   *mongo.Database
+	Name       string
+	Member     string
+	Partitions int
+	Lease      time.Duration
+
+	source  *mongo.Collection
+	handler ProjectionHandler
+}
+
+// InitConsumerGroup panics unless partitions is positive and lease at
+// least a millisecond.
+func InitConsumerGroup(
+	database *mongo.Database,
+	name string,
+	member string,
+	partitions int,
+	lease time.Duration,
+) *ConsumerGroup {
+	cg := &ConsumerGroup{
+		Database:   database,
+		Name:       name,
+		Member:     member,
+		Partitions: partitions,
+		Lease:      lease,
+	}
+
+	if err := cg.validate(); err != nil {
+		panic(err)
+	}
+
+	return cg
+}
+
+func (cg *ConsumerGroup) validate() error {
+	if cg.Partitions <= 0 {
+		return fmt.Errorf("%w: consumer group %s needs at least one partition", ErrValidation, cg.Name)
+	}
+
+	if cg.Lease < time.Millisecond {
+		return fmt.Errorf("%w: consumer group %s lease %v is below 1ms", ErrValidation, cg.Name, cg.Lease)
+	}
+
+	return nil
+}
+
+func (cg *ConsumerGroup) Consume(source ChangeSource, handler ProjectionHandler) {
+	cg.source = source.GetCollection()
+	cg.handler = handler
+}
+
+// PartitionOf returns the partition of a change event's document key.
+func PartitionOf(documentKey bson.Raw, partitions int) int {
+	hash := fnv.New32a()
+	hash.Write(documentKey)
+
+	return int(hash.Sum32() % uint32(partitions))
+}
+
+func (cg *ConsumerGroup) collection() *mongo.Collection {
+	return cg.Database.Collection(ConsumerGroupCollection)
+}
+
+func (cg *ConsumerGroup) partitionID(partition int) string {
+	return fmt.Sprintf("%s:partition:%d", cg.Name, partition)
+}
+
+// Run heartbeats, rebalances and consumes the owned partitions until ctx
+// is cancelled or a handler fails. Leases are released on return.
+func (cg *ConsumerGroup) Run(ctx context.Context) error {
+	if err := cg.validate(); err != nil {
+		return err
+	}
+
+	ctx, done, err := beginWorker(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type consumer struct {
+		stop context.CancelFunc
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		owned   = map[int]*consumer{}
+		failMu  sync.Mutex
+		failure error
+	)
+
+	fail := func(err error) {
+		failMu.Lock()
+		defer failMu.Unlock()
+
+		if failure == nil {
+			failure = err
+			cancel()
+		}
+	}
+
+	defer func() {
+		mu.Lock()
+
+		for partition, c := range owned {
+			c.stop()
+			cg.release(context.Background(), partition)
+		}
+
+		mu.Unlock()
+		wg.Wait()
+		cg.collection().DeleteOne(context.Background(), bson.M{"_id": cg.memberID()})
+	}()
+
+	ticker := time.NewTicker(cg.Lease / 3)
+	defer ticker.Stop()
+
+	for {
+		desired, err := cg.rebalance(ctx)
+
+		if err != nil {
+			fail(err)
+		}
+
+		mu.Lock()
+
+		for partition, c := range owned {
+			if !desired[partition] {
+				c.stop()
+				delete(owned, partition)
+
+				if err = cg.release(ctx, partition); err != nil {
+					fail(err)
+				}
+			}
+		}
+
+		for partition := range desired {
+			acquired, err := cg.acquire(ctx, partition)
+
+			if err != nil {
+				fail(err)
+			}
+
+			c, running := owned[partition]
+
+			switch {
+			case acquired && !running:
+				partitionCtx, stop := context.WithCancel(ctx)
+				c = &consumer{stop: stop}
+				owned[partition] = c
+				wg.Add(1)
+
+				go func(partition int, c *consumer) {
+					defer wg.Done()
+
+					err := cg.consume(partitionCtx, partition)
+
+					mu.Lock()
+
+					if owned[partition] == c {
+						delete(owned, partition)
+					}
+
+					mu.Unlock()
+
+					// A lost lease is retaken or handed over on the next tick.
+					if err != nil && partitionCtx.Err() == nil && !errors.Is(err, errPartitionLost) {
+						fail(err)
+					}
+				}(partition, c)
+			case !acquired && running:
+				c.stop()
+				delete(owned, partition)
+			}
+		}
+
+		mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			failMu.Lock()
+			defer failMu.Unlock()
+
+			return failure
+		case <-ticker.C:
+		}
+	}
+}
+
+func (cg *ConsumerGroup) memberID() string {
+	return cg.Name + ":member:" + cg.Member
+}
+
+// rebalance renews this member's heartbeat and returns the partitions it
+// should own: the live members sorted by name take turns.
+func (cg *ConsumerGroup) rebalance(ctx context.Context) (map[int]bool, error) {
+	now := time.Now()
+
+	_, err := cg.collection().UpdateOne(
+		ctx,
+		bson.M{"_id": cg.memberID()},
+		bson.M{"$set": bson.M{"group": cg.Name, "kind": "member", "member": cg.Member, "expires_at": now.Add(cg.Lease)}},
+		options.Update().SetUpsert(true),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := cg.collection().Find(ctx, bson.M{
+		"group":      cg.Name,
+		"kind":       "member",
+		"expires_at": bson.M{"$gt": now},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var members []struct {
+		Member string `bson:"member"`
+	}
+
+	if err = cursor.All(ctx, &members); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(members))
+
+	for _, member := range members {
+		names = append(names, member.Member)
+	}
+
+	sort.Strings(names)
+	index := sort.SearchStrings(names, cg.Member)
+	desired := map[int]bool{}
+
+	for partition := index; partition < cg.Partitions && len(names) > 0; partition += len(names) {
+		desired[partition] = true
+	}
+
+	return desired, nil
+}
+
+// acquire claims or renews the lease of a partition.
+func (cg *ConsumerGroup) acquire(ctx context.Context, partition int) (bool, error) {
+	now := time.Now()
+
+	_, err := cg.collection().UpdateOne(
+		ctx,
+		bson.M{
+			"_id": cg.partitionID(partition),
+			"$or": bson.A{
+				bson.M{"owner": cg.Member},
+				bson.M{"expires_at": bson.M{"$lte": now}},
+			},
+		},
+		bson.M{"$set": bson.M{
+			"group":      cg.Name,
+			"kind":       "partition",
+			"owner":      cg.Member,
+			"expires_at": now.Add(cg.Lease),
+		}},
+		options.Update().SetUpsert(true),
+	)
+
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (cg *ConsumerGroup) release(ctx context.Context, partition int) error {
+	_, err := cg.collection().UpdateOne(
+		ctx,
+		bson.M{"_id": cg.partitionID(partition), "owner": cg.Member},
+		bson.M{"$set": bson.M{"expires_at": time.Time{}}},
+	)
+
+	return err
+}
+
+func (cg *ConsumerGroup) consume(ctx context.Context, partition int) error {
+	var state struct {
+		Token bson.Raw `bson:"token"`
+	}
+
+	err := cg.collection().FindOne(ctx, bson.M{"_id": cg.partitionID(partition)}).Decode(&state)
+
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+	if state.Token != nil {
+		opts.SetResumeAfter(state.Token)
+	}
+
+	stream, err := cg.source.Watch(ctx, mongo.Pipeline{}, opts)
+
+	if err != nil {
+		return err
+	}
+
+	defer stream.Close(ctx)
+
+	var saved time.Time
+
+	for stream.Next(ctx) {
+		var event ChangeEvent
+
+		if err = stream.Decode(&event); err != nil {
+			return err
+		}
+
+		mine := PartitionOf(event.DocumentKey, cg.Partitions) == partition
+
+		if mine {
+			if err = cg.handler(ctx, event); err != nil {
+				return err
+			}
+		}
+
+		if mine || time.Since(saved) >= tokenSaveInterval {
+			if err = cg.saveToken(ctx, partition, stream.ResumeToken()); err != nil {
+				return err
+			}
+
+			saved = time.Now()
+		}
+	}
+
+	return stream.Err()
+}
+
+// saveToken stores the resume position only while this member owns the
+// partition, and stops the consumer once it no longer does.
+func (cg *ConsumerGroup) saveToken(ctx context.Context, partition int, token bson.Raw) error {
+	result, err := cg.collection().UpdateOne(
+		ctx,
+		bson.M{"_id": cg.partitionID(partition), "owner": cg.Member, "expires_at": bson.M{"$gt": time.Now()}},
+		bson.M{"$set": bson.M{"token": token, "updated_at": time.Now()}},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w: %s partition %d", errPartitionLost, cg.Name, partition)
+	}
+
+	return nil
+}
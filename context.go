@@ -0,0 +1,65 @@
+package remongo
+
+import "context"
+
+type requestIDKey struct{}
+
+type tenantKey struct{}
+
+type actorKey struct{}
+
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+
+	return requestID
+}
+
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+
+	return tenant
+}
+
+// ContextWithActor records who performs the operations run under ctx.
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+
+	return actor
+}
+
+// WithContext returns a copy of the repository whose operations run
+// under ctx instead of context.TODO().
+func (mr *MongoRepository[T]) WithContext(ctx context.Context) IMongoRepository[T] {
+	clone := *mr
+	clone.ctx = ctx
+
+	return &clone
+}
+
+// ForRequest returns a view of the repository bound to the request ctx:
+// operations join the session started on it (mongo.NewSessionContext),
+// resolve its tenant, tag queries with its actor and request id and stop
+// at its deadline.
+func (mr *MongoRepository[T]) ForRequest(ctx context.Context) IMongoRepository[T] {
+	return mr.WithContext(ctx)
+}
+
+func (mr *MongoRepository[T]) context() context.Context {
+	if mr.ctx != nil {
+		return mr.ctx
+	}
+
+	return context.TODO()
+}
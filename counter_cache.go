@@ -0,0 +1,133 @@
+package remongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CounterCache keeps CounterField of the Target documents equal to the
+// number of Source documents whose ForeignKey holds their _id, e.g. a
+// post's comments_count, by following the change stream of Source.
+// Deletes and foreign key changes are only seen with pre-images, see
+// Enable. Events delivered twice after a crash skew the counters until
+// Reconcile recounts them.
+type CounterCache struct {
+	Database     *mongo.Database
+	Source       *mongo.Collection
+	Target       *mongo.Collection
+	ForeignKey   string
+	CounterField string
+}
+
+func InitCounterCache(source ChangeSource, target ChangeSource, foreignKey string, counterField string) *CounterCache {
+	collection := source.GetCollection()
+
+	return &CounterCache{
+		Database:     collection.Database(),
+		Source:       collection,
+		Target:       target.GetCollection(),
+		ForeignKey:   foreignKey,
+		CounterField: counterField,
+	}
+}
+
+func (cc *CounterCache) name() string {
+	return "counter:" + cc.Source.Name() + ":" + cc.Target.Name() + "." + cc.CounterField
+}
+
+// Enable turns on the change stream pre-images of Source (MongoDB 6.0).
+func (cc *CounterCache) Enable(ctx context.Context) error {
+	return cc.Database.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: cc.Source.Name()},
+		{Key: "changeStreamPreAndPostImages", Value: bson.M{"enabled": true}},
+	}).Err()
+}
+
+// Run applies the changes of Source to the counters until ctx is
+// cancelled, resuming where the previous run stopped.
+func (cc *CounterCache) Run(ctx context.Context) error {
+	ctx, done, err := beginWorker(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	opts := options.ChangeStream().
+		SetFullDocument(options.WhenAvailable).
+		SetFullDocumentBeforeChange(options.WhenAvailable)
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{
+		"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}},
+	}}}}
+
+	return followStream(ctx, cc.Source, cc.name(), pipeline, opts, cc.apply)
+}
+
+func (cc *CounterCache) apply(ctx context.Context, event ChangeEvent) error {
+	before, hadBefore := cc.foreignKey(event.FullDocumentBeforeChange)
+	after, hasAfter := cc.foreignKey(event.FullDocument)
+
+	if event.OperationType == "delete" {
+		hasAfter = false
+	}
+
+	if hadBefore && hasAfter && before.Equal(after) {
+		return nil
+	}
+
+	if hadBefore {
+		if err := cc.increment(ctx, before, -1); err != nil {
+			return err
+		}
+	}
+
+	if hasAfter && (event.OperationType == "insert" || event.FullDocumentBeforeChange != nil) {
+		return cc.increment(ctx, after, 1)
+	}
+
+	return nil
+}
+
+func (cc *CounterCache) foreignKey(doc bson.Raw) (bson.RawValue, bool) {
+	if doc == nil {
+		return bson.RawValue{}, false
+	}
+
+	value, err := doc.LookupErr(cc.ForeignKey)
+
+	return value, err == nil && value.Type != bson.TypeNull
+}
+
+func (cc *CounterCache) increment(ctx context.Context, id bson.RawValue, delta int) error {
+	_, err := cc.Target.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{cc.CounterField: delta}})
+
+	return err
+}
+
+// Reconcile recounts every counter from Source.
+func (cc *CounterCache) Reconcile(ctx context.Context) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{
+			"from":         cc.Source.Name(),
+			"localField":   "_id",
+			"foreignField": cc.ForeignKey,
+			"pipeline":     bson.A{bson.M{"$project": bson.M{"_id": 1}}},
+			"as":           "_children",
+		}}},
+		{{Key: "$project", Value: bson.M{cc.CounterField: bson.M{"$size": "$_children"}}}},
+		mergeStage(cc.Target.Name(), &MergeOptions{On: []string{"_id"}, WhenMatched: "merge", WhenNotMatched: "discard"}),
+	}
+
+	cursor, err := cc.Target.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+
+	if err != nil {
+		return err
+	}
+
+	return cursor.Close(ctx)
+}
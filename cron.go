@@ -0,0 +1,195 @@
+package remongo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed five-field cron expression (minute, hour,
+// day of month, month, day of week) or a fixed "@every" interval.
+type CronSchedule struct {
+	Spec string
+
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+	every   time.Duration
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func ParseCron(spec string) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	schedule := &CronSchedule{Spec: spec}
+
+	if strings.HasPrefix(spec, "@every ") {
+		every, err := time.ParseDuration(strings.TrimSpace(spec[len("@every "):]))
+
+		if err != nil {
+			return nil, fmt.Errorf("remongo: invalid cron spec %q: %w", spec, err)
+		}
+
+		if every < time.Second {
+			return nil, fmt.Errorf("remongo: invalid cron spec %q: interval below one second", spec)
+		}
+
+		schedule.every = every
+
+		return schedule, nil
+	}
+
+	expr := spec
+
+	if descriptor, ok := cronDescriptors[spec]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("remongo: invalid cron spec %q: expected 5 fields", spec)
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	targets := [5]*uint64{
+		&schedule.minute,
+		&schedule.hour,
+		&schedule.dom,
+		&schedule.month,
+		&schedule.dow,
+	}
+
+	for i, field := range fields {
+		bits, err := parseCronField(field, bounds[i][0], bounds[i][1])
+
+		if err != nil {
+			return nil, fmt.Errorf("remongo: invalid cron spec %q: %w", spec, err)
+		}
+
+		*targets[i] = bits
+	}
+
+	// Sunday may be written as either 0 or 7.
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+
+	schedule.domStar = fields[2] == "*"
+	schedule.dowStar = fields[4] == "*"
+
+	return schedule, nil
+}
+
+func parseCronField(field string, min int, max int) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		rangePart := part
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("bad step in %q", part)
+			}
+
+			step = s
+			rangePart = part[:i]
+		}
+
+		lo, hi := min, max
+
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			l, err := strconv.Atoi(bounds[0])
+
+			if err != nil {
+				return 0, fmt.Errorf("bad value in %q", part)
+			}
+
+			lo, hi = l, l
+
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("bad value in %q", part)
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value out of range in %q", part)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// Next returns the first activation strictly after t, or the zero time
+// when the expression cannot be satisfied within five years.
+func (cs *CronSchedule) Next(t time.Time) time.Time {
+	if cs.every > 0 {
+		return t.Add(cs.every)
+	}
+
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + 5
+
+	for t.Year() <= limit {
+		if cs.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if !cs.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if cs.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+
+		if cs.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (cs *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := cs.dom&(1<<uint(t.Day())) != 0
+	dowMatch := cs.dow&(1<<uint(t.Weekday())) != 0
+
+	if cs.domStar || cs.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
@@ -0,0 +1,148 @@
+package remongo
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WithBatchSize returns a copy of the repository whose cursors fetch
+// size documents per round trip.
+func (mr *MongoRepository[T]) WithBatchSize(size int32) IMongoRepository[T] {
+	clone := *mr
+	clone.BatchSize = size
+
+	return &clone
+}
+
+// WithNoCursorTimeout returns a copy of the repository whose cursors are
+// not reaped by the server after ten idle minutes, for long scans that
+// process slowly. Such cursors must be exhausted or closed.
+func (mr *MongoRepository[T]) WithNoCursorTimeout() IMongoRepository[T] {
+	clone := *mr
+	clone.NoCursorTimeout = true
+
+	return &clone
+}
+
+// toPipeline accepts a mongo.Pipeline, a slice of stages or a single stage.
+func toPipeline(aggregate interface{}) mongo.Pipeline {
+	switch v := aggregate.(type) {
+	case mongo.Pipeline:
+		return v
+	case []bson.D:
+		return v
+	case bson.D:
+		return mongo.Pipeline{v}
+	case bson.A:
+		pipeline := mongo.Pipeline{}
+
+		for _, stage := range v {
+			if doc, err := ToBson(stage); err == nil {
+				pipeline = append(pipeline, *doc)
+			}
+		}
+
+		return pipeline
+	case []interface{}:
+		return toPipeline(bson.A(v))
+	default:
+		if doc, err := ToBson(v); err == nil {
+			return mongo.Pipeline{*doc}
+		}
+
+		return nil
+	}
+}
+
+// findStages translates the options of a Find with an aggregate into the
+// stages that follow the pipeline and the options of the aggregation.
+// Options without an equivalent fail with ErrValidation.
+func findStages(opts []*options.FindOptions) (mongo.Pipeline, *options.AggregateOptions, error) {
+	var (
+		sort, projection interface{}
+		skip, limit      *int64
+	)
+
+	aggregate := options.Aggregate()
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		if opt.AllowPartialResults != nil || opt.CursorType != nil || opt.Max != nil || opt.Min != nil ||
+			opt.MaxAwaitTime != nil || opt.NoCursorTimeout != nil || opt.OplogReplay != nil ||
+			opt.ReturnKey != nil || opt.ShowRecordID != nil || opt.Snapshot != nil {
+			return nil, nil, fmt.Errorf("%w: Find with an aggregate supports sort, skip, limit, projection, "+
+				"collation, hint, comment, batch size, max time, allowDiskUse and let", ErrValidation)
+		}
+
+		if opt.Sort != nil {
+			sort = opt.Sort
+		}
+
+		if opt.Projection != nil {
+			projection = opt.Projection
+		}
+
+		if opt.Skip != nil {
+			skip = opt.Skip
+		}
+
+		if opt.Limit != nil {
+			limit = opt.Limit
+		}
+
+		if opt.Collation != nil {
+			aggregate.SetCollation(opt.Collation)
+		}
+
+		if opt.Hint != nil {
+			aggregate.SetHint(opt.Hint)
+		}
+
+		if opt.Comment != nil {
+			aggregate.SetComment(*opt.Comment)
+		}
+
+		if opt.BatchSize != nil {
+			aggregate.SetBatchSize(*opt.BatchSize)
+		}
+
+		if opt.MaxTime != nil {
+			aggregate.SetMaxTime(*opt.MaxTime)
+		}
+
+		if opt.AllowDiskUse != nil {
+			aggregate.SetAllowDiskUse(*opt.AllowDiskUse)
+		}
+
+		if opt.Let != nil {
+			aggregate.SetLet(opt.Let)
+		}
+	}
+
+	stages := mongo.Pipeline{}
+
+	if sort != nil {
+		stages = append(stages, bson.D{{Key: "$sort", Value: sort}})
+	}
+
+	if skip != nil && *skip > 0 {
+		stages = append(stages, bson.D{{Key: "$skip", Value: *skip}})
+	}
+
+	// A negative limit means a single batch of that many to find.
+	if limit != nil && *limit != 0 {
+		stages = append(stages, bson.D{{Key: "$limit", Value: max(*limit, -*limit)}})
+	}
+
+	if projection != nil {
+		stages = append(stages, bson.D{{Key: "$project", Value: projection}})
+	}
+
+	return stages, aggregate, nil
+}
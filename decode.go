@@ -0,0 +1,95 @@
+package remongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// decode is the single path through which documents read by the
+// repository become models, so read-side features apply everywhere.
+func (mr *MongoRepository[T]) decode(ctx context.Context, raw bson.Raw, model *T) error {
+	raw, err := mr.migrate(ctx, raw)
+
+	if err != nil {
+		return err
+	}
+
+	if raw, err = mr.readRenamed(raw); err != nil {
+		return err
+	}
+
+	if err = mr.checkStrict(raw, model); err != nil {
+		return err
+	}
+
+	if mr.DecodeMode == DecodeLenient {
+		err = mr.unmarshalLenient(ctx, raw, model)
+	} else {
+		err = mr.unmarshal(raw, model)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return mr.afterRead(ctx, model)
+}
+
+func (mr *MongoRepository[T]) decodeCursor(ctx context.Context, cursor *mongo.Cursor) ([]T, error) {
+	defer cursor.Close(ctx)
+
+	models := []T{}
+
+	for cursor.Next(ctx) {
+		var model T
+
+		if err := mr.decode(ctx, cursor.Current, &model); err != nil {
+			return nil, err
+		}
+
+		models = append(models, model)
+	}
+
+	return models, cursor.Err()
+}
+
+// prepareDocument is the single path through which models become
+// documents on insert and replace. It returns the encoded document so
+// that its size is checked before it is sent.
+func (mr *MongoRepository[T]) prepareDocument(ctx context.Context, model interface{}) (interface{}, error) {
+	if err := mr.beforeWrite(ctx, model); err != nil {
+		return nil, err
+	}
+
+	if err := mr.validate(model); err != nil {
+		return nil, err
+	}
+
+	if err := mr.checkShardKey(model); err != nil {
+		return nil, err
+	}
+
+	doc, err := mr.stampSchemaVersion(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := mr.marshal(doc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if raw, err = mr.writeRenamed(raw); err != nil {
+		return nil, err
+	}
+
+	if err = checkDocumentSize(raw); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,243 @@
+package remongo
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Denormalization copies fields of Source documents into the Target
+// documents referencing them through ForeignKey, e.g. users.name into
+// orders.user_name for the orders whose user_id is the user's _id.
+// Fields maps source fields to target fields. Run propagates changes in
+// the background; Backfill and Check cover documents written before it
+// ran or while it was down.
+type Denormalization struct {
+	Source     *mongo.Collection
+	Target     *mongo.Collection
+	ForeignKey string
+	Fields     map[string]string
+}
+
+// DenormalizationReport is the outcome of Check. Stale holds up to the
+// requested number of target _ids whose copies differ from the source.
+type DenormalizationReport struct {
+	Checked int64
+	Stale   []interface{}
+}
+
+func InitDenormalization(
+	source ChangeSource,
+	target ChangeSource,
+	foreignKey string,
+	fields map[string]string,
+) *Denormalization {
+	return &Denormalization{
+		Source:     source.GetCollection(),
+		Target:     target.GetCollection(),
+		ForeignKey: foreignKey,
+		Fields:     fields,
+	}
+}
+
+func (d *Denormalization) name() string {
+	return "denormalize:" + d.Source.Name() + ":" + d.Target.Name() + "." + d.ForeignKey
+}
+
+// sourceFields returns the mapped source fields in a stable order.
+func (d *Denormalization) sourceFields() []string {
+	fields := make([]string, 0, len(d.Fields))
+
+	for field := range d.Fields {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	return fields
+}
+
+// Run propagates updates and replaces of Source until ctx is cancelled,
+// resuming where the previous run stopped. Deleted sources leave their
+// copies in place.
+func (d *Denormalization) Run(ctx context.Context) error {
+	ctx, done, err := beginWorker(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{
+		"operationType": bson.M{"$in": bson.A{"update", "replace"}},
+	}}}}
+
+	return followStream(ctx, d.Source, d.name(), pipeline, options.ChangeStream().SetFullDocument(options.UpdateLookup), d.apply)
+}
+
+func (d *Denormalization) apply(ctx context.Context, event ChangeEvent) error {
+	if event.FullDocument == nil || event.OperationType == "update" && !d.touched(event.UpdateDescription) {
+		return nil
+	}
+
+	id, err := event.DocumentKey.LookupErr("_id")
+
+	if err != nil {
+		return err
+	}
+
+	set, unset := bson.M{}, bson.M{}
+
+	for _, field := range d.sourceFields() {
+		value, err := event.FullDocument.LookupErr(strings.Split(field, ".")...)
+
+		if err != nil {
+			unset[d.Fields[field]] = ""
+		} else {
+			set[d.Fields[field]] = value
+		}
+	}
+
+	update := bson.M{}
+
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	_, err = d.Target.UpdateMany(ctx, bson.M{d.ForeignKey: id}, update)
+
+	return err
+}
+
+// touched reports whether an update changed a mapped field or a path
+// above or below one.
+func (d *Denormalization) touched(description *UpdateDescription) bool {
+	if description == nil {
+		return true
+	}
+
+	changed := append([]string{}, description.RemovedFields...)
+
+	if elements, err := description.UpdatedFields.Elements(); err == nil {
+		for _, element := range elements {
+			changed = append(changed, element.Key())
+		}
+	}
+
+	for _, path := range changed {
+		for field := range d.Fields {
+			if path == field || strings.HasPrefix(path, field+".") || strings.HasPrefix(field, path+".") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// lookupStages join every target document with its source as _source.
+func (d *Denormalization) lookupStages() mongo.Pipeline {
+	projection := bson.M{}
+
+	for field := range d.Fields {
+		projection[field] = 1
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{
+			"from":         d.Source.Name(),
+			"localField":   d.ForeignKey,
+			"foreignField": "_id",
+			"pipeline":     bson.A{bson.M{"$project": projection}},
+			"as":           "_source",
+		}}},
+		{{Key: "$unwind", Value: "$_source"}},
+	}
+}
+
+// Backfill rewrites the copies of every Target document that has a
+// source.
+func (d *Denormalization) Backfill(ctx context.Context) error {
+	copies := bson.M{}
+
+	for source, target := range d.Fields {
+		copies[target] = "$_source." + source
+	}
+
+	pipeline := append(d.lookupStages(),
+		bson.D{{Key: "$project", Value: copies}},
+		mergeStage(d.Target.Name(), &MergeOptions{On: []string{"_id"}, WhenMatched: "merge", WhenNotMatched: "discard"}),
+	)
+
+	cursor, err := d.Target.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+
+	if err != nil {
+		return err
+	}
+
+	return cursor.Close(ctx)
+}
+
+// Check compares the copies of every Target document with its source and
+// reports up to limit stale ones.
+func (d *Denormalization) Check(ctx context.Context, limit int) (*DenormalizationReport, error) {
+	differs := bson.A{}
+
+	for _, source := range d.sourceFields() {
+		differs = append(differs, bson.M{"$ne": bson.A{"$" + d.Fields[source], "$_source." + source}})
+	}
+
+	pipeline := append(d.lookupStages(),
+		bson.D{{Key: "$project", Value: bson.M{"stale": bson.M{"$or": differs}}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"checked": bson.A{bson.M{"$count": "n"}},
+			"stale":   bson.A{bson.M{"$match": bson.M{"stale": true}}, bson.M{"$limit": max(limit, 1)}},
+		}}},
+	)
+
+	cursor, err := d.Target.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		Checked []struct {
+			N int64 `bson:"n"`
+		} `bson:"checked"`
+		Stale []struct {
+			ID interface{} `bson:"_id"`
+		} `bson:"stale"`
+	}
+
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	report := &DenormalizationReport{Stale: []interface{}{}}
+
+	if len(results) == 0 {
+		return report, nil
+	}
+
+	if len(results[0].Checked) > 0 {
+		report.Checked = results[0].Checked[0].N
+	}
+
+	for _, stale := range results[0].Stale {
+		if len(report.Stale) < limit {
+			report.Stale = append(report.Stale, stale.ID)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,24 @@
+package remongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Document is a schemaless model for collections without a Go struct.
+// Its collection comes from the repository rather than the model.
+type Document bson.M
+
+func (d Document) Collection() string {
+	return ""
+}
+
+type DocumentRepository = MongoRepository[Document]
+
+func InitDocumentRepository(database *mongo.Database, collection string) *DocumentRepository {
+	return &DocumentRepository{
+		Database:   database,
+		Collection: collection,
+		Model:      Document{},
+	}
+}
@@ -0,0 +1,110 @@
+package remongo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MaxDocumentSize is the server limit for a single BSON document.
+const MaxDocumentSize = 16 * 1024 * 1024
+
+type FieldSize struct {
+	Path string
+	Size int
+}
+
+// DocumentTooLargeError is returned before a write whose document
+// exceeds MaxDocumentSize. Fields lists the largest paths, biggest first.
+// It matches ErrTooLarge.
+type DocumentTooLargeError struct {
+	Size   int
+	Fields []FieldSize
+}
+
+func (e *DocumentTooLargeError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+
+	for _, field := range e.Fields {
+		parts = append(parts, fmt.Sprintf("%s (%d bytes)", field.Path, field.Size))
+	}
+
+	return fmt.Sprintf(
+		"remongo: document is %d bytes, over the %d byte limit; largest fields: %s",
+		e.Size, MaxDocumentSize, strings.Join(parts, ", "),
+	)
+}
+
+func (e *DocumentTooLargeError) Is(target error) bool {
+	return target == ErrTooLarge
+}
+
+const reportedFields = 5
+
+func checkDocumentSize(raw bson.Raw) error {
+	if len(raw) <= MaxDocumentSize {
+		return nil
+	}
+
+	elements, err := raw.Elements()
+
+	if err != nil {
+		return err
+	}
+
+	fields := make([]FieldSize, 0, len(elements))
+
+	for _, element := range elements {
+		fields = append(fields, largestPath(element.Key(), element))
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Size > fields[j].Size
+	})
+
+	if len(fields) > reportedFields {
+		fields = fields[:reportedFields]
+	}
+
+	return &DocumentTooLargeError{Size: len(raw), Fields: fields}
+}
+
+// largestPath follows the biggest child of element for as long as it
+// holds at least half of its parent, so the path points at the data
+// that is actually responsible for the size.
+func largestPath(path string, element bson.RawElement) FieldSize {
+	size := len(element)
+	value := element.Value()
+
+	nested, ok := value.DocumentOK()
+
+	if !ok {
+		nested, ok = value.ArrayOK()
+	}
+
+	if !ok {
+		return FieldSize{Path: path, Size: size}
+	}
+
+	children, err := nested.Elements()
+
+	if err != nil || len(children) == 0 {
+		return FieldSize{Path: path, Size: size}
+	}
+
+	biggest := children[0]
+
+	for _, child := range children[1:] {
+		if len(child) > len(biggest) {
+			biggest = child
+		}
+	}
+
+	if len(biggest)*2 < size {
+		return FieldSize{Path: path, Size: size}
+	}
+
+	return largestPath(path+"."+biggest.Key(), biggest)
+}
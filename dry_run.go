@@ -0,0 +1,68 @@
+package remongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const dryRunSampleSize = 10
+
+// WithDryRun returns a copy of the repository whose destructive writes
+// only report the documents they would touch.
+func (mr *MongoRepository[T]) WithDryRun() IMongoRepository[T] {
+	clone := *mr
+	clone.DryRun = true
+
+	return &clone
+}
+
+func (mr *MongoRepository[T]) dryRun(op string, filter interface{}, limit int64) (error, int64) {
+	ctx := mr.context()
+	coll := mr.collection(ctx)
+
+	countOpts := options.Count()
+
+	if limit > 0 {
+		countOpts.SetLimit(limit)
+	}
+
+	count, err := coll.CountDocuments(ctx, filter, countOpts)
+
+	if err != nil {
+		return err, 0
+	}
+
+	cursor, err := coll.Find(
+		ctx,
+		filter,
+		options.Find().
+			SetProjection(bson.M{"_id": 1}).
+			SetLimit(dryRunSampleSize),
+	)
+
+	if err != nil {
+		return err, 0
+	}
+
+	var sample []bson.M
+
+	if err = cursor.All(ctx, &sample); err != nil {
+		return err, 0
+	}
+
+	ids := make([]interface{}, 0, len(sample))
+
+	for _, doc := range sample {
+		ids = append(ids, doc["_id"])
+	}
+
+	mr.logger().Printf(
+		"remongo: dry run %s on %s would affect %d document(s), sample ids: %v",
+		op,
+		coll.Name(),
+		count,
+		ids,
+	)
+
+	return nil, count
+}
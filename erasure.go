@@ -0,0 +1,167 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErasureHandler deletes or anonymizes the documents of one subject and
+// returns how many were affected.
+type ErasureHandler func(ctx context.Context, subjectID string) (int64, error)
+
+type erasureStep struct {
+	name    string
+	handler ErasureHandler
+}
+
+// ErasureResult is the outcome of one registered handler.
+type ErasureResult struct {
+	Name     string
+	Affected int64
+	Err      error
+}
+
+// ErasureReport records what EraseSubject did. Transactional is false
+// when the deployment does not support transactions and the handlers ran
+// one after the other.
+type ErasureReport struct {
+	SubjectID     string
+	StartedAt     time.Time
+	FinishedAt    time.Time
+	Transactional bool
+	Results       []ErasureResult
+}
+
+func (r *ErasureReport) Affected() int64 {
+	var total int64
+
+	for _, result := range r.Results {
+		total += result.Affected
+	}
+
+	return total
+}
+
+func (r *ErasureReport) Err() error {
+	errs := []error{}
+
+	for _, result := range r.Results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Erasure coordinates right-to-erasure requests across repositories.
+type Erasure struct {
+	Client *mongo.Client
+
+	mu    sync.Mutex
+	steps []erasureStep
+}
+
+func InitErasure(client *mongo.Client) *Erasure {
+	return &Erasure{Client: client}
+}
+
+// Register adds a handler; handlers run in registration order.
+func (e *Erasure) Register(name string, handler ErasureHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.steps = append(e.steps, erasureStep{name: name, handler: handler})
+}
+
+// EraseBy returns a handler deleting the documents of repo whose field
+// holds the subject ID.
+func EraseBy[T IMongoModel](repo IMongoRepository[T], field string) ErasureHandler {
+	return func(ctx context.Context, subjectID string) (int64, error) {
+		err, deleted := repo.ForRequest(ctx).DeleteMany(bson.M{field: subjectID})
+
+		return deleted, err
+	}
+}
+
+// EraseSubject runs every handler for subjectID. On replica sets and
+// sharded clusters they share one transaction, so a failure rolls back
+// all of them; otherwise each runs on its own and the report shows which
+// ones failed.
+func (e *Erasure) EraseSubject(ctx context.Context, subjectID string) (*ErasureReport, error) {
+	e.mu.Lock()
+	steps := append([]erasureStep(nil), e.steps...)
+	e.mu.Unlock()
+
+	report := &ErasureReport{SubjectID: subjectID, StartedAt: time.Now()}
+
+	transactional, err := supportsTransactions(ctx, e.Client)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if transactional {
+		err = e.eraseInTransaction(ctx, steps, report)
+	} else {
+		report.Results = runErasure(ctx, steps, subjectID)
+		err = report.Err()
+	}
+
+	report.FinishedAt = time.Now()
+
+	return report, err
+}
+
+func (e *Erasure) eraseInTransaction(ctx context.Context, steps []erasureStep, report *ErasureReport) error {
+	session, err := e.Client.StartSession()
+
+	if err != nil {
+		return err
+	}
+
+	defer session.EndSession(ctx)
+
+	report.Transactional = true
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		report.Results = runErasure(sc, steps, report.SubjectID)
+
+		return nil, report.Err()
+	})
+
+	return err
+}
+
+func runErasure(ctx context.Context, steps []erasureStep, subjectID string) []ErasureResult {
+	results := make([]ErasureResult, 0, len(steps))
+
+	for _, step := range steps {
+		affected, err := step.handler(ctx, subjectID)
+		results = append(results, ErasureResult{Name: step.name, Affected: affected, Err: err})
+	}
+
+	return results
+}
+
+// supportsTransactions reports whether the deployment is a replica set
+// or a sharded cluster.
+func supportsTransactions(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+
+	err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+
+	if err != nil {
+		return false, err
+	}
+
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
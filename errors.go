@@ -0,0 +1,195 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver"
+)
+
+// The stable error set returned by the repository. Driver errors are
+// classified into these while remaining reachable through errors.As.
+var (
+	ErrNotFound     = errors.New("remongo: document not found")
+	ErrDuplicateKey = errors.New("remongo: duplicate key")
+	ErrTimeout      = errors.New("remongo: operation timed out")
+	ErrTransient    = errors.New("remongo: transient failure")
+	ErrValidation   = errors.New("remongo: validation failed")
+	ErrTooLarge     = errors.New("remongo: document too large")
+)
+
+const (
+	codeDocumentValidationFailure = 121
+	codeBSONObjectTooLarge        = 10334
+)
+
+// classifiedError tags a driver error with one of the package errors.
+type classifiedError struct {
+	kind error
+	err  error
+}
+
+func (e *classifiedError) Error() string {
+	return e.kind.Error() + ": " + e.err.Error()
+}
+
+func (e *classifiedError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *classifiedError) Unwrap() error {
+	return e.err
+}
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+func IsDuplicateKey(err error) bool {
+	return errors.Is(err, ErrDuplicateKey)
+}
+
+func IsTimeout(err error) bool {
+	return errors.Is(err, ErrTimeout)
+}
+
+// IsTransient reports failures that are expected to succeed on retry,
+// such as network errors, elections and transient transaction errors.
+func IsTransient(err error) bool {
+	return errors.Is(err, ErrTransient)
+}
+
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+func IsTooLarge(err error) bool {
+	return errors.Is(err, ErrTooLarge)
+}
+
+// DuplicateKeyError is returned for E11000 failures. It matches
+// ErrDuplicateKey with errors.Is and unwraps to the driver error.
+type DuplicateKeyError struct {
+	Index  string
+	Fields []string
+	Key    bson.Raw
+	Err    error
+}
+
+func (e *DuplicateKeyError) Error() string {
+	if e.Index != "" {
+		return "remongo: duplicate key on index " + e.Index
+	}
+
+	return ErrDuplicateKey.Error()
+}
+
+func (e *DuplicateKeyError) Is(target error) bool {
+	return target == ErrDuplicateKey
+}
+
+func (e *DuplicateKeyError) Unwrap() error {
+	return e.Err
+}
+
+var duplicateIndexPattern = regexp.MustCompile(`index: (\S+)`)
+
+// translateError maps driver errors onto the package error types.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if kind := classify(err); kind != nil {
+		if errors.Is(err, kind) {
+			return err
+		}
+
+		if kind == ErrDuplicateKey {
+			return newDuplicateKeyError(err)
+		}
+
+		return &classifiedError{kind: kind, err: err}
+	}
+
+	return err
+}
+
+// translateWriteError classifies a single write error of a bulk write.
+func translateWriteError(we mongo.WriteError) error {
+	return translateError(mongo.WriteException{WriteErrors: mongo.WriteErrors{we}})
+}
+
+func classify(err error) error {
+	var se mongo.ServerError
+	isServerError := errors.As(err, &se)
+
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, mongo.ErrNoDocuments):
+		return ErrNotFound
+	case errors.Is(err, ErrDuplicateKey), mongo.IsDuplicateKeyError(err):
+		return ErrDuplicateKey
+	case errors.Is(err, ErrValidation),
+		isServerError && se.HasErrorCode(codeDocumentValidationFailure):
+		return ErrValidation
+	case errors.Is(err, ErrTooLarge),
+		errors.Is(err, driver.ErrDocumentTooLarge),
+		isServerError && se.HasErrorCode(codeBSONObjectTooLarge):
+		return ErrTooLarge
+	case errors.Is(err, ErrTimeout),
+		errors.Is(err, context.DeadlineExceeded),
+		mongo.IsTimeout(err):
+		return ErrTimeout
+	case errors.Is(err, ErrTransient),
+		mongo.IsNetworkError(err),
+		isServerError && (se.HasErrorLabel("TransientTransactionError") ||
+			se.HasErrorLabel("RetryableWriteError")):
+		return ErrTransient
+	}
+
+	return nil
+}
+
+func newDuplicateKeyError(err error) *DuplicateKeyError {
+	dup := &DuplicateKeyError{Err: err}
+
+	var raw bson.Raw
+	var message string
+	var we mongo.WriteException
+	var bwe mongo.BulkWriteException
+	var ce mongo.CommandError
+
+	switch {
+	case errors.As(err, &we) && len(we.WriteErrors) > 0:
+		raw, message = we.WriteErrors[0].Raw, we.WriteErrors[0].Message
+	case errors.As(err, &bwe) && len(bwe.WriteErrors) > 0:
+		raw, message = bwe.WriteErrors[0].Raw, bwe.WriteErrors[0].Message
+	case errors.As(err, &ce):
+		raw, message = ce.Raw, ce.Message
+	default:
+		message = err.Error()
+	}
+
+	if match := duplicateIndexPattern.FindStringSubmatch(message); match != nil {
+		dup.Index = match[1]
+	}
+
+	if raw != nil {
+		if pattern, ok := raw.Lookup("keyPattern").DocumentOK(); ok {
+			if elements, err := pattern.Elements(); err == nil {
+				for _, element := range elements {
+					dup.Fields = append(dup.Fields, element.Key())
+				}
+			}
+		}
+
+		if value, ok := raw.Lookup("keyValue").DocumentOK(); ok {
+			dup.Key = value
+		}
+	}
+
+	return dup
+}
@@ -0,0 +1,258 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrWrongExpectedVersion = errors.New("remongo: wrong expected stream version")
+
+const (
+	// AnyVersion appends regardless of the stream version.
+	AnyVersion int64 = -1
+	// NoStream expects the stream to have no events yet.
+	NoStream int64 = 0
+)
+
+// eventPositionID names the counter of global positions in the events
+// collection.
+const eventPositionID = "remongo_event_position"
+
+// EventData is an event to append. Data must encode as a document.
+type EventData struct {
+	Type     string
+	Data     interface{}
+	Metadata map[string]interface{}
+}
+
+type StoredEvent struct {
+	ID         primitive.ObjectID     `bson:"_id"`
+	StreamID   string                 `bson:"stream_id"`
+	Version    int64                  `bson:"version"`
+	Position   int64                  `bson:"position"`
+	Type       string                 `bson:"type"`
+	Data       bson.Raw               `bson:"data"`
+	Metadata   map[string]interface{} `bson:"metadata,omitempty"`
+	RecordedAt time.Time              `bson:"recorded_at"`
+}
+
+// Decode unmarshals the event data into v.
+func (se StoredEvent) Decode(v interface{}) error {
+	return bson.Unmarshal(se.Data, v)
+}
+
+// EventStoreRepository stores events in an append-only collection. Each
+// stream numbers its events from 1, a unique index on stream and version
+// rejecting concurrent appends, and every event gets an increasing
+// global position for ReadAll. Positions are reserved before the insert,
+// so an append committed late may land behind a reader that already
+// paged past its position; readers needing every event should re-read a
+// short window behind their last position.
+type EventStoreRepository struct {
+	Client     *mongo.Client
+	Collection *mongo.Collection
+}
+
+func InitEventStoreRepository(database *mongo.Database, collection string) *EventStoreRepository {
+	return &EventStoreRepository{
+		Client:     database.Client(),
+		Collection: database.Collection(collection),
+	}
+}
+
+func (es *EventStoreRepository) GetCollection() *mongo.Collection {
+	return es.Collection
+}
+
+func (es *EventStoreRepository) counters() *mongo.Collection {
+	return es.Collection.Database().Collection(es.Collection.Name() + "_positions")
+}
+
+// EnsureIndexes creates the indexes the versioning and reads rely on.
+func (es *EventStoreRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := es.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "stream_id", Value: 1}, {Key: "version", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "position", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+
+	return err
+}
+
+// AppendToStream appends events to streamID if its version is still
+// expectedVersion, or whatever it is with AnyVersion, and returns the new
+// version. A stale expectedVersion fails with ErrWrongExpectedVersion.
+// The events are appended all or none where the deployment supports
+// transactions.
+func (es *EventStoreRepository) AppendToStream(
+	ctx context.Context,
+	streamID string,
+	expectedVersion int64,
+	events ...EventData,
+) (int64, error) {
+	if len(events) == 0 {
+		return es.StreamVersion(ctx, streamID)
+	}
+
+	transactional, err := supportsTransactions(ctx, es.Client)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !transactional {
+		return es.append(ctx, streamID, expectedVersion, events)
+	}
+
+	session, err := es.Client.StartSession()
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer session.EndSession(ctx)
+
+	version, err := session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return es.append(sc, streamID, expectedVersion, events)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return version.(int64), nil
+}
+
+func (es *EventStoreRepository) append(ctx context.Context, streamID string, expectedVersion int64, events []EventData) (int64, error) {
+	current, err := es.StreamVersion(ctx, streamID)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if expectedVersion != AnyVersion && current != expectedVersion {
+		return 0, fmt.Errorf("%w: %s is at %d, expected %d", ErrWrongExpectedVersion, streamID, current, expectedVersion)
+	}
+
+	var counter struct {
+		Position int64 `bson:"position"`
+	}
+
+	err = es.counters().FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": eventPositionID},
+		bson.M{"$inc": bson.M{"position": int64(len(events))}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+
+	if err != nil {
+		return 0, err
+	}
+
+	first := counter.Position - int64(len(events)) + 1
+	now := time.Now()
+	docs := make([]interface{}, len(events))
+
+	for i, event := range events {
+		doc := bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "stream_id", Value: streamID},
+			{Key: "version", Value: current + int64(i) + 1},
+			{Key: "position", Value: first + int64(i)},
+			{Key: "type", Value: event.Type},
+			{Key: "data", Value: event.Data},
+			{Key: "recorded_at", Value: now},
+		}
+
+		if len(event.Metadata) > 0 {
+			doc = append(doc, bson.E{Key: "metadata", Value: event.Metadata})
+		}
+
+		docs[i] = doc
+	}
+
+	_, err = es.Collection.InsertMany(ctx, docs)
+
+	if mongo.IsDuplicateKeyError(err) {
+		return 0, fmt.Errorf("%w: %s was appended to concurrently", ErrWrongExpectedVersion, streamID)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return current + int64(len(events)), nil
+}
+
+// StreamVersion returns the version of the last event of streamID, or
+// NoStream.
+func (es *EventStoreRepository) StreamVersion(ctx context.Context, streamID string) (int64, error) {
+	var last struct {
+		Version int64 `bson:"version"`
+	}
+
+	err := es.Collection.FindOne(
+		ctx,
+		bson.M{"stream_id": streamID},
+		options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}).SetProjection(bson.M{"version": 1}),
+	).Decode(&last)
+
+	if err == mongo.ErrNoDocuments {
+		return NoStream, nil
+	}
+
+	return last.Version, err
+}
+
+// ReadStream returns up to limit events of streamID from fromVersion on,
+// or all of them when limit is 0.
+func (es *EventStoreRepository) ReadStream(ctx context.Context, streamID string, fromVersion int64, limit int64) ([]StoredEvent, error) {
+	return es.read(ctx, bson.M{"stream_id": streamID, "version": bson.M{"$gte": fromVersion}}, "version", limit)
+}
+
+// ReadAll pages through every stream in position order: it returns up to
+// limit events after position and the position to pass for the next
+// page, which is position itself when there were none.
+func (es *EventStoreRepository) ReadAll(ctx context.Context, position int64, limit int64) ([]StoredEvent, int64, error) {
+	events, err := es.read(ctx, bson.M{"position": bson.M{"$gt": position}}, "position", limit)
+
+	if err != nil {
+		return nil, position, err
+	}
+
+	if len(events) > 0 {
+		position = events[len(events)-1].Position
+	}
+
+	return events, position, nil
+}
+
+func (es *EventStoreRepository) read(ctx context.Context, filter bson.M, sortField string, limit int64) ([]StoredEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: sortField, Value: 1}})
+
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+
+	cursor, err := es.Collection.Find(ctx, filter, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	events := []StoredEvent{}
+
+	return events, cursor.All(ctx, &events)
+}
@@ -0,0 +1,101 @@
+package remongo
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ExportHandler returns the documents of one subject.
+type ExportHandler func(ctx context.Context, subjectID string) ([]bson.Raw, error)
+
+type exportSource struct {
+	name    string
+	handler ExportHandler
+}
+
+// ExportBundle is the JSON document written by ExportSubject. Documents
+// are in relaxed extended JSON.
+type ExportBundle struct {
+	SubjectID  string          `json:"subject_id"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Sources    []ExportSection `json:"sources"`
+}
+
+type ExportSection struct {
+	Name      string            `json:"name"`
+	Documents []json.RawMessage `json:"documents"`
+}
+
+// SubjectExporter collects a subject's documents across repositories for
+// data-portability requests.
+type SubjectExporter struct {
+	mu      sync.Mutex
+	sources []exportSource
+}
+
+func InitSubjectExporter() *SubjectExporter {
+	return &SubjectExporter{}
+}
+
+// Register adds a source; sources appear in the bundle in registration
+// order.
+func (e *SubjectExporter) Register(name string, handler ExportHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.sources = append(e.sources, exportSource{name: name, handler: handler})
+}
+
+// ExportBy returns a handler reading the documents of repo whose field
+// holds the subject ID.
+func ExportBy[T IMongoModel](repo IMongoRepository[T], field string) ExportHandler {
+	return func(ctx context.Context, subjectID string) ([]bson.Raw, error) {
+		return repo.FindRaw(ctx, bson.M{field: subjectID})
+	}
+}
+
+// ExportSubject writes every registered source's documents for subjectID
+// to w as one ExportBundle. Nothing is written if a source fails.
+func (e *SubjectExporter) ExportSubject(ctx context.Context, subjectID string, w io.Writer) error {
+	e.mu.Lock()
+	sources := append([]exportSource(nil), e.sources...)
+	e.mu.Unlock()
+
+	bundle := ExportBundle{
+		SubjectID:  subjectID,
+		ExportedAt: time.Now().UTC(),
+		Sources:    make([]ExportSection, 0, len(sources)),
+	}
+
+	for _, source := range sources {
+		docs, err := source.handler(ctx, subjectID)
+
+		if err != nil {
+			return err
+		}
+
+		section := ExportSection{Name: source.name, Documents: make([]json.RawMessage, 0, len(docs))}
+
+		for _, doc := range docs {
+			data, err := bson.MarshalExtJSON(doc, false, false)
+
+			if err != nil {
+				return err
+			}
+
+			section.Documents = append(section.Documents, data)
+		}
+
+		bundle.Sources = append(bundle.Sources, section)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(bundle)
+}
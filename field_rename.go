@@ -0,0 +1,201 @@
+package remongo
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// FieldRename moves a top-level field from Old to New without downtime:
+// while it is declared, inserts, replaces and updates write both names,
+// and reads fall back to Old for documents that only have it. Run
+// Backfill once every instance dual-writes, then FinalizeRenames, then
+// drop the declaration and rebuild the repositories.
+type FieldRename struct {
+	Old string
+	New string
+}
+
+// WithFieldRename declares that the model field stored as old is now
+// stored as new.
+func WithFieldRename(old string, new string) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.renames = append(c.renames, FieldRename{Old: old, New: new})
+	}
+}
+
+// readRenamed copies old fields into the new names the model decodes.
+func (mr *MongoRepository[T]) readRenamed(raw bson.Raw) (bson.Raw, error) {
+	if len(mr.Renames) == 0 {
+		return raw, nil
+	}
+
+	var extra bson.D
+
+	for _, rename := range mr.Renames {
+		if _, err := raw.LookupErr(rename.New); err == nil {
+			continue
+		}
+
+		if value, err := raw.LookupErr(rename.Old); err == nil {
+			extra = append(extra, bson.E{Key: rename.New, Value: value})
+		}
+	}
+
+	if len(extra) == 0 {
+		return raw, nil
+	}
+
+	return appendFields(raw, extra)
+}
+
+// writeRenamed adds the old name of every renamed field to an encoded
+// document.
+func (mr *MongoRepository[T]) writeRenamed(raw bson.Raw) (bson.Raw, error) {
+	var extra bson.D
+
+	for _, rename := range mr.Renames {
+		if _, err := raw.LookupErr(rename.Old); err == nil {
+			continue
+		}
+
+		if value, err := raw.LookupErr(rename.New); err == nil {
+			extra = append(extra, bson.E{Key: rename.Old, Value: value})
+		}
+	}
+
+	if len(extra) == 0 {
+		return raw, nil
+	}
+
+	return appendFields(raw, extra)
+}
+
+func appendFields(raw bson.Raw, extra bson.D) (bson.Raw, error) {
+	elements, err := raw.Elements()
+
+	if err != nil {
+		return nil, err
+	}
+
+	doc := make(bson.D, 0, len(elements)+len(extra))
+
+	for _, element := range elements {
+		doc = append(doc, bson.E{Key: element.Key(), Value: element.Value()})
+	}
+
+	return bson.Marshal(append(doc, extra...))
+}
+
+// renameUpdate mirrors the operators of an update that touch a renamed
+// field, or a path below it, onto the old name. Pipeline updates are
+// left as they are.
+func (mr *MongoRepository[T]) renameUpdate(update interface{}) interface{} {
+	if len(mr.Renames) == 0 || update == nil {
+		return update
+	}
+
+	doc, err := toDocument(update)
+
+	if err != nil || len(doc) == 0 || !strings.HasPrefix(doc[0].Key, "$") {
+		return update
+	}
+
+	mirrored := make(bson.D, 0, len(doc))
+
+	for _, operator := range doc {
+		fields, err := toDocument(operator.Value)
+
+		if err != nil || operator.Key == "$rename" {
+			mirrored = append(mirrored, operator)
+
+			continue
+		}
+
+		present := map[string]bool{}
+
+		for _, field := range fields {
+			present[field.Key] = true
+		}
+
+		for _, field := range fields {
+			for _, rename := range mr.Renames {
+				if field.Key != rename.New && !strings.HasPrefix(field.Key, rename.New+".") {
+					continue
+				}
+
+				old := rename.Old + strings.TrimPrefix(field.Key, rename.New)
+
+				if !present[old] {
+					fields = append(fields, bson.E{Key: old, Value: field.Value})
+				}
+			}
+		}
+
+		mirrored = append(mirrored, bson.E{Key: operator.Key, Value: fields})
+	}
+
+	return mirrored
+}
+
+// Backfill copies every declared rename's old field into the new one in
+// the documents that lack it, so that queries on the new name match them.
+func (mr *MongoRepository[T]) Backfill(ctx context.Context) (int64, error) {
+	var modified int64
+
+	for _, rename := range mr.Renames {
+		filter := bson.M{rename.Old: bson.M{"$exists": true}, rename.New: bson.M{"$exists": false}}
+		update := mongo.Pipeline{{{Key: "$set", Value: bson.M{rename.New: "$" + rename.Old}}}}
+
+		err := mr.runContext(ctx, "Backfill", filter, update, func(ctx context.Context, op *Operation) error {
+			result, err := mr.collection(ctx).UpdateMany(ctx, op.Filter, op.Update)
+
+			if err == nil {
+				modified += result.ModifiedCount
+			}
+
+			return err
+		})
+
+		if err != nil {
+			return modified, err
+		}
+	}
+
+	return modified, nil
+}
+
+// FinalizeRenames backfills once more and then removes the old fields.
+// The repository is left unchanged: drop the WithFieldRename
+// declarations and rebuild it, on every instance, before writing again,
+// or the writes add the old fields back.
+func (mr *MongoRepository[T]) FinalizeRenames(ctx context.Context) (int64, error) {
+	if _, err := mr.Backfill(ctx); err != nil {
+		return 0, err
+	}
+
+	var modified int64
+
+	for _, rename := range mr.Renames {
+		filter := bson.M{rename.Old: bson.M{"$exists": true}}
+		update := bson.M{"$unset": bson.M{rename.Old: ""}}
+
+		err := mr.runContext(ctx, "FinalizeRenames", filter, update, func(ctx context.Context, op *Operation) error {
+			result, err := mr.collection(ctx).UpdateMany(ctx, op.Filter, op.Update)
+
+			if err == nil {
+				modified += result.ModifiedCount
+			}
+
+			return err
+		})
+
+		if err != nil {
+			return modified, err
+		}
+	}
+
+	return modified, nil
+}
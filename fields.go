@@ -0,0 +1,240 @@
+package remongo
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type bsonField struct {
+	Name      string
+	Index     []int
+	Type      reflect.Type
+	OmitEmpty bool
+}
+
+// bsonFields lists the fields of a struct type under the names the bson
+// codec uses for them, flattening inline structs.
+func bsonFields(t reflect.Type) []bsonField {
+	t = indirectType(t)
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := []bsonField{}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag, ok := sf.Tag.Lookup("bson")
+
+		if !ok && sf.Tag != "" && !strings.Contains(string(sf.Tag), ":") {
+			tag = string(sf.Tag)
+		}
+
+		if tag == "-" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		field := bsonField{Index: sf.Index, Type: sf.Type}
+		inline := false
+
+		for _, flag := range parts[1:] {
+			switch flag {
+			case "omitempty":
+				field.OmitEmpty = true
+			case "inline":
+				inline = true
+			}
+		}
+
+		if inline && indirectType(sf.Type).Kind() == reflect.Struct {
+			for _, nested := range bsonFields(sf.Type) {
+				nested.Index = append([]int{i}, nested.Index...)
+				fields = append(fields, nested)
+			}
+
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+
+		field.Name = name
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// ValidatePath checks that a dotted path such as "address.city" or
+// "items.0.sku" refers to a field declared by model.
+func ValidatePath(model interface{}, path string) error {
+	t := reflect.TypeOf(model)
+
+	if path == "_id" {
+		return nil
+	}
+
+	if t == nil {
+		return fmt.Errorf("remongo: cannot validate %q against a nil model", path)
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		t = indirectType(t)
+
+		switch t.Kind() {
+		case reflect.Slice, reflect.Array:
+			t = t.Elem()
+
+			if _, err := strconv.Atoi(segment); err == nil || segment == "$" || strings.HasPrefix(segment, "$[") {
+				continue
+			}
+
+			t = indirectType(t)
+		}
+
+		switch t.Kind() {
+		case reflect.Map, reflect.Interface:
+			return nil
+		case reflect.Struct:
+			if t.PkgPath() == "go.mongodb.org/mongo-driver/bson/primitive" {
+				return nil
+			}
+		default:
+			return fmt.Errorf("remongo: field %q not found on %T", path, model)
+		}
+
+		found := false
+
+		for _, field := range bsonFields(t) {
+			if field.Name == segment {
+				t = field.Type
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("remongo: field %q not found on %T", path, model)
+		}
+	}
+
+	return nil
+}
+
+// FieldPath returns the dotted bson path of the Go fields of T, e.g.
+// FieldPath[User]("Address", "City") is "address.city". Slices are
+// stepped through, as in queries on array elements.
+func FieldPath[T any](fields ...string) (string, error) {
+	var model T
+	t := reflect.TypeOf(&model).Elem()
+	segments := make([]string, 0, len(fields))
+
+	for _, name := range fields {
+		t = elementType(t)
+		found := false
+
+		for _, field := range bsonFields(t) {
+			if t.FieldByIndex(field.Index).Name == name {
+				segments = append(segments, field.Name)
+				t = field.Type
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return "", fmt.Errorf("remongo: field %s not found on %T", strings.Join(fields, "."), model)
+		}
+	}
+
+	return strings.Join(segments, "."), nil
+}
+
+// PathOf returns the dotted bson path of the field that ptr points to
+// inside model, e.g. PathOf(&u, &u.Address.City) is "address.city".
+// Fields behind pointers are found only when the pointers are set.
+func PathOf(model interface{}, ptr interface{}) (string, error) {
+	v := reflect.ValueOf(model)
+	target := reflect.ValueOf(ptr)
+
+	if v.Kind() != reflect.Ptr || target.Kind() != reflect.Ptr || v.IsNil() || target.IsNil() {
+		return "", fmt.Errorf("remongo: PathOf needs pointers to a model and one of its fields")
+	}
+
+	if path, ok := findPath(v.Elem(), target); ok {
+		return path, nil
+	}
+
+	return "", fmt.Errorf("remongo: %T is not a field of %T", ptr, model)
+}
+
+// MustPath panics if FieldPath or PathOf failed, for paths built in
+// package-level variables.
+func MustPath(path string, err error) string {
+	if err != nil {
+		panic(err)
+	}
+
+	return path
+}
+
+func findPath(v reflect.Value, target reflect.Value) (string, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+
+	for _, field := range bsonFields(v.Type()) {
+		value, err := v.FieldByIndexErr(field.Index)
+
+		if err != nil {
+			continue
+		}
+
+		if value.Addr().Pointer() == target.Pointer() && value.Type() == target.Type().Elem() {
+			return field.Name, true
+		}
+
+		if nested, ok := findPath(value, target); ok {
+			return field.Name + "." + nested, true
+		}
+	}
+
+	return "", false
+}
+
+func elementType(t reflect.Type) reflect.Type {
+	t = indirectType(t)
+
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = indirectType(t.Elem())
+	}
+
+	return t
+}
@@ -0,0 +1,95 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MissingIDsError is returned with the partial result of FindByIDs when
+// some ids have no document. It matches ErrNotFound.
+type MissingIDsError struct {
+	IDs []interface{}
+}
+
+func (e *MissingIDsError) Error() string {
+	return fmt.Sprintf("remongo: %d of the requested ids were not found", len(e.IDs))
+}
+
+func (e *MissingIDsError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// FindByIDs fetches the documents for ids with a single $in query and
+// returns them in the order of ids. Missing ids are reported through a
+// *MissingIDsError alongside the documents that were found.
+func (mr *MongoRepository[T]) FindByIDs(ctx context.Context, ids []interface{}) ([]T, error) {
+	parsed := make([]interface{}, 0, len(ids))
+
+	for _, id := range ids {
+		value, err := mr.parseID(id)
+
+		if err != nil {
+			return nil, mr.wrapError("FindByIDs", nil, err)
+		}
+
+		parsed = append(parsed, value)
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": parsed}}
+	found := map[string]T{}
+
+	err := mr.runContext(ctx, "FindByIDs", filter, nil, func(ctx context.Context, op *Operation) error {
+		cursor, err := mr.collection(ctx).Find(ctx, op.Filter, mr.findOptions(ctx, nil)...)
+
+		if err != nil {
+			return err
+		}
+
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			var model T
+
+			if err = mr.decode(ctx, cursor.Current, &model); err != nil {
+				return err
+			}
+
+			found[idKey(cursor.Current.Lookup("_id"))] = model
+		}
+
+		return cursor.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]T, 0, len(parsed))
+	missing := []interface{}{}
+
+	for i, id := range parsed {
+		t, data, err := bson.MarshalValue(id)
+
+		if err != nil {
+			return nil, mr.wrapError("FindByIDs", filter, err)
+		}
+
+		if model, ok := found[idKey(bson.RawValue{Type: t, Value: data})]; ok {
+			models = append(models, model)
+		} else {
+			missing = append(missing, ids[i])
+		}
+	}
+
+	if len(missing) > 0 {
+		return models, &MissingIDsError{IDs: missing}
+	}
+
+	return models, nil
+}
+
+func idKey(id bson.RawValue) string {
+	return string(rune(id.Type)) + string(id.Value)
+}
@@ -0,0 +1,27 @@
+package remongo
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindOneSorted decodes the first document matching filter in sort order.
+func (mr *MongoRepository[T]) FindOneSorted(model *T, filter interface{}, sort Sort) error {
+	if err := sort.Validate(mr.Model); err != nil {
+		return mr.wrapError("FindOne", filter, err)
+	}
+
+	return mr.FindOne(model, filter, sort.FindOneOptions())
+}
+
+// FindOneProjected decodes only the projected fields of the first match.
+func (mr *MongoRepository[T]) FindOneProjected(model *T, filter interface{}, projection Projection) error {
+	return mr.FindOne(model, filter, options.FindOne().SetProjection(bson.D(projection)))
+}
+
+// FindOneWithin fails the query server-side once maxTime has elapsed.
+func (mr *MongoRepository[T]) FindOneWithin(model *T, filter interface{}, maxTime time.Duration) error {
+	return mr.FindOne(model, filter, options.FindOne().SetMaxTime(maxTime))
+}
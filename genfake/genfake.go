@@ -0,0 +1,340 @@
+// Package genfake fills model structs with realistic random data for
+// load tests and demo environments.
+//
+// Fields are generated from their `fake:"..."` tag, e.g.
+//
+//	type User struct {
+//		ID    primitive.ObjectID `bson:"_id"`
+//		Name  string             `fake:"name"`
+//		Email string             `fake:"email"`
+//		Age   int                `fake:"int:18,90"`
+//		Plan  string             `fake:"oneof:free|pro|team"`
+//	}
+//
+// Supported hints are name, first_name, last_name, email, phone,
+// username, word, sentence, paragraph, city, country, street, url, uuid,
+// date, past, future, int:min,max, float:min,max, oneof:a|b|c and "-" to
+// leave a field zero. Untagged fields get a random value of their kind;
+// nested structs, pointers, slices and maps are filled recursively.
+package genfake
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oneapplab/remongo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	firstNames = []string{"Ada", "Ben", "Chloe", "Diego", "Emma", "Farid", "Grace", "Hiro", "Ines", "Jonas", "Kira", "Liam", "Maya", "Noah", "Olga", "Priya", "Quentin", "Rosa", "Sven", "Tara", "Umar", "Vera", "Wen", "Yara", "Zane"}
+	lastNames  = []string{"Anders", "Baker", "Castillo", "Dubois", "Eriksen", "Fischer", "Gomez", "Hansen", "Ito", "Jovanovic", "Kowalski", "Larsen", "Moreau", "Nakamura", "Okafor", "Petrov", "Rossi", "Silva", "Tanaka", "Vargas", "Weber", "Yilmaz", "Zhang"}
+	words      = []string{"alpha", "bright", "cloud", "delta", "ember", "forest", "granite", "harbor", "island", "jade", "kernel", "lumen", "meadow", "nova", "orbit", "prism", "quartz", "river", "summit", "timber", "umbra", "vertex", "willow", "zenith"}
+	cities     = []string{"Amsterdam", "Berlin", "Cairo", "Dublin", "Lisbon", "Madrid", "Nairobi", "Osaka", "Paris", "Quito", "Seoul", "Toronto", "Vienna", "Warsaw"}
+	countries  = []string{"Austria", "Brazil", "Canada", "Denmark", "Egypt", "France", "Germany", "India", "Japan", "Kenya", "Mexico", "Norway", "Portugal", "Spain"}
+	domains    = []string{"example.com", "example.org", "example.net", "mail.test"}
+	streets    = []string{"Main St", "Oak Ave", "Station Rd", "Harbour Way", "Park Ln", "Mill St"}
+)
+
+// Generator produces random values from its own source, so runs with
+// the same seed yield the same data.
+type Generator struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func New(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed))}
+}
+
+var defaultGenerator = New(time.Now().UnixNano())
+
+// Generate returns n random instances of T.
+func Generate[T any](n int) []T {
+	return GenerateWith[T](defaultGenerator, n)
+}
+
+func GenerateWith[T any](g *Generator, n int) []T {
+	items := make([]T, n)
+
+	for i := range items {
+		g.Fill(&items[i])
+	}
+
+	return items
+}
+
+// Insert generates n models and inserts them batchSize at a time. It
+// stops at the first batch with failures.
+func Insert[T remongo.IMongoModel](ctx context.Context, repo remongo.IMongoRepository[T], n int, batchSize int) error {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	for n > 0 {
+		size := min(batchSize, n)
+
+		result, err := repo.InsertManyBatch(ctx, Generate[T](size))
+
+		if err != nil {
+			return err
+		}
+
+		if failed := result.Failed(); len(failed) > 0 {
+			return failed[0].Err
+		}
+
+		n -= size
+	}
+
+	return nil
+}
+
+// Fill sets every exported field of the struct v points to.
+func (g *Generator) Fill(v interface{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.fill(reflect.ValueOf(v).Elem(), "", 0)
+}
+
+const maxDepth = 5
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	objectIDType = reflect.TypeOf(primitive.ObjectID{})
+)
+
+func (g *Generator) fill(v reflect.Value, hint string, depth int) {
+	if hint == "-" || depth > maxDepth {
+		return
+	}
+
+	if hint != "" && g.fillHint(v, hint) {
+		return
+	}
+
+	switch v.Type() {
+	case timeType:
+		v.Set(reflect.ValueOf(g.date(-365*24*time.Hour, 0)))
+
+		return
+	case objectIDType:
+		v.Set(reflect.ValueOf(primitive.NewObjectID()))
+
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(g.pick(words))
+	case reflect.Bool:
+		v.SetBool(g.rand.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(g.rand.Int63n(min(1000, maxInt(v.Type())) + 1))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(g.rand.Int63n(min(1000, maxInt(v.Type())) + 1)))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(int(g.rand.Float64()*100000)) / 100)
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		g.fill(v.Elem(), hint, depth+1)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, 16)
+			g.rand.Read(data)
+			v.SetBytes(data)
+
+			return
+		}
+
+		n := 1 + g.rand.Intn(3)
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+
+		for i := 0; i < n; i++ {
+			g.fill(v.Index(i), hint, depth+1)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+
+		v.Set(reflect.MakeMap(v.Type()))
+
+		for i := 1 + g.rand.Intn(3); i > 0; i-- {
+			value := reflect.New(v.Type().Elem()).Elem()
+			g.fill(value, hint, depth+1)
+			v.SetMapIndex(reflect.ValueOf(g.pick(words)).Convert(v.Type().Key()), value)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+
+			if field.IsExported() {
+				g.fill(v.Field(i), field.Tag.Get("fake"), depth+1)
+			}
+		}
+	}
+}
+
+// fillHint reports whether hint applied to the kind of v.
+func (g *Generator) fillHint(v reflect.Value, hint string) bool {
+	name, param, _ := strings.Cut(hint, ":")
+
+	switch name {
+	case "int":
+		low, high := bounds(param, 0, 1000)
+
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v.SetInt(int64(low) + g.rand.Int63n(int64(high-low)+1))
+
+			return true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v.SetUint(uint64(low) + uint64(g.rand.Int63n(int64(high-low)+1)))
+
+			return true
+		}
+
+		return false
+	case "float":
+		low, high := bounds(param, 0, 1)
+
+		if v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64 {
+			v.SetFloat(low + g.rand.Float64()*(high-low))
+
+			return true
+		}
+
+		return false
+	case "date", "past", "future":
+		if v.Type() != timeType {
+			return false
+		}
+
+		switch name {
+		case "past":
+			v.Set(reflect.ValueOf(g.date(-5*365*24*time.Hour, 0)))
+		case "future":
+			v.Set(reflect.ValueOf(g.date(0, 365*24*time.Hour)))
+		default:
+			v.Set(reflect.ValueOf(g.date(-365*24*time.Hour, 365*24*time.Hour)))
+		}
+
+		return true
+	}
+
+	if v.Kind() != reflect.String {
+		return false
+	}
+
+	var s string
+
+	switch name {
+	case "name":
+		s = g.pick(firstNames) + " " + g.pick(lastNames)
+	case "first_name":
+		s = g.pick(firstNames)
+	case "last_name":
+		s = g.pick(lastNames)
+	case "email":
+		s = strings.ToLower(g.pick(firstNames)+"."+g.pick(lastNames)) + strconv.Itoa(g.rand.Intn(100)) + "@" + g.pick(domains)
+	case "username":
+		s = strings.ToLower(g.pick(firstNames)) + "_" + g.pick(words) + strconv.Itoa(g.rand.Intn(1000))
+	case "phone":
+		s = fmt.Sprintf("+1-555-%03d-%04d", g.rand.Intn(1000), g.rand.Intn(10000))
+	case "word":
+		s = g.pick(words)
+	case "sentence":
+		s = g.sentence(4 + g.rand.Intn(8))
+	case "paragraph":
+		sentences := make([]string, 3+g.rand.Intn(3))
+
+		for i := range sentences {
+			sentences[i] = g.sentence(4 + g.rand.Intn(8))
+		}
+
+		s = strings.Join(sentences, " ")
+	case "city":
+		s = g.pick(cities)
+	case "country":
+		s = g.pick(countries)
+	case "street":
+		s = strconv.Itoa(1+g.rand.Intn(300)) + " " + g.pick(streets)
+	case "url":
+		s = "https://" + g.pick(domains) + "/" + g.pick(words)
+	case "uuid":
+		data := make([]byte, 16)
+		g.rand.Read(data)
+		data[6] = data[6]&0x0f | 0x40
+		data[8] = data[8]&0x3f | 0x80
+		s = fmt.Sprintf("%x-%x-%x-%x-%x", data[0:4], data[4:6], data[6:8], data[8:10], data[10:])
+	case "oneof":
+		s = g.pick(strings.Split(param, "|"))
+	default:
+		return false
+	}
+
+	v.SetString(s)
+
+	return true
+}
+
+func (g *Generator) pick(options []string) string {
+	return options[g.rand.Intn(len(options))]
+}
+
+func (g *Generator) sentence(n int) string {
+	parts := make([]string, n)
+
+	for i := range parts {
+		parts[i] = g.pick(words)
+	}
+
+	s := strings.Join(parts, " ")
+
+	return strings.ToUpper(s[:1]) + s[1:] + "."
+}
+
+// date returns a time between now+from and now+to.
+func (g *Generator) date(from time.Duration, to time.Duration) time.Time {
+	return time.Now().Add(from + time.Duration(g.rand.Int63n(int64(to-from)+1))).Truncate(time.Millisecond)
+}
+
+func bounds(param string, low float64, high float64) (float64, float64) {
+	if lowText, highText, ok := strings.Cut(param, ","); ok {
+		if l, err := strconv.ParseFloat(strings.TrimSpace(lowText), 64); err == nil {
+			low = l
+		}
+
+		if h, err := strconv.ParseFloat(strings.TrimSpace(highText), 64); err == nil {
+			high = h
+		}
+	}
+
+	if high < low {
+		low, high = high, low
+	}
+
+	return low, high
+}
+
+func maxInt(t reflect.Type) int64 {
+	bits := t.Bits()
+
+	if t.Kind() >= reflect.Uint && t.Kind() <= reflect.Uint64 {
+		bits++
+	}
+
+	if bits >= 64 {
+		return 1<<63 - 1
+	}
+
+	return 1<<(bits-1) - 1
+}
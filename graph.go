@@ -0,0 +1,255 @@
+package remongo
+
+import (
+	"context"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const graphDepthField = "_remongo_depth"
+
+// GraphNode is a document reached by Traverse, Depth hops away from the
+// nearest start document, which have depth 0.
+type GraphNode[T any] struct {
+	ID    interface{}
+	Depth int
+	Model T
+}
+
+// Traversal is the result of Traverse. Each cycle lists the _ids of the
+// nodes on it, in edge order.
+type Traversal[T any] struct {
+	Nodes  []GraphNode[T]
+	Cycles [][]interface{}
+}
+
+// Traverse follows the edges from the connectFrom field of a document to
+// the documents whose connectTo field holds the same value, starting
+// from those matching startFilter, for at most maxDepth hops or without
+// limit when maxDepth is 0, e.g. connectFrom "follows" and connectTo
+// "_id" for a social graph. Nodes are visited once, ordered by depth, and
+// the cycles among them are reported. Scopes and the default filter
+// restrict every node; an Authorizer only sees the start filter.
+func (mr *MongoRepository[T]) Traverse(
+	ctx context.Context,
+	startFilter interface{},
+	connectFrom string,
+	connectTo string,
+	maxDepth int,
+) (*Traversal[T], error) {
+	query, err := toFilter(startFilter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		Start   bson.Raw   `bson:"start"`
+		Reached []bson.Raw `bson:"_reached"`
+	}
+
+	err = mr.runContext(ctx, "Traverse", query, nil, func(ctx context.Context, op *Operation) error {
+		lookup := bson.D{
+			{Key: "from", Value: mr.collection(ctx).Name()},
+			{Key: "startWith", Value: "$" + connectFrom},
+			{Key: "connectFromField", Value: connectFrom},
+			{Key: "connectToField", Value: connectTo},
+			{Key: "as", Value: "_reached"},
+			{Key: "depthField", Value: graphDepthField},
+		}
+
+		if maxDepth > 0 {
+			lookup = append(lookup, bson.E{Key: "maxDepth", Value: maxDepth - 1})
+		}
+
+		// Reached documents are held to the same scopes and default
+		// filter as the start documents.
+		scope, err := mr.restriction(ctx, op)
+
+		if err != nil {
+			return err
+		}
+
+		if scope != nil {
+			lookup = append(lookup, bson.E{Key: "restrictSearchWithMatch", Value: scope})
+		}
+
+		return mr.aggregateAll(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: op.Filter}},
+			{{Key: "$graphLookup", Value: lookup}},
+			{{Key: "$project", Value: bson.M{"_reached": 1, "start": "$$ROOT"}}},
+			{{Key: "$unset", Value: "start._reached"}},
+		}, &results)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	depths := map[string]int{}
+	docs := map[string]bson.Raw{}
+	order := []string{}
+
+	visit := func(raw bson.Raw, depth int) {
+		key := idKey(raw.Lookup("_id"))
+
+		if known, ok := depths[key]; ok && known <= depth {
+			return
+		}
+
+		if _, ok := depths[key]; !ok {
+			order = append(order, key)
+		}
+
+		depths[key], docs[key] = depth, raw
+	}
+
+	for _, result := range results {
+		visit(result.Start, 0)
+	}
+
+	for _, result := range results {
+		for _, raw := range result.Reached {
+			depth, _ := raw.Lookup(graphDepthField).AsInt64OK()
+			visit(raw, int(depth)+1)
+		}
+	}
+
+	traversal := &Traversal[T]{Nodes: make([]GraphNode[T], 0, len(order))}
+
+	for _, key := range order {
+		raw, err := withoutField(docs[key], graphDepthField)
+
+		if err != nil {
+			return nil, err
+		}
+
+		node := GraphNode[T]{Depth: depths[key]}
+
+		if err = raw.Lookup("_id").Unmarshal(&node.ID); err != nil {
+			return nil, err
+		}
+
+		if err = mr.decode(ctx, raw, &node.Model); err != nil {
+			return nil, err
+		}
+
+		traversal.Nodes = append(traversal.Nodes, node)
+	}
+
+	sort.SliceStable(traversal.Nodes, func(i, j int) bool {
+		return traversal.Nodes[i].Depth < traversal.Nodes[j].Depth
+	})
+
+	traversal.Cycles = findCycles(order, docs, connectFrom, connectTo)
+
+	return traversal, nil
+}
+
+func withoutField(raw bson.Raw, field string) (bson.Raw, error) {
+	elements, err := raw.Elements()
+
+	if err != nil {
+		return nil, err
+	}
+
+	doc := make(bson.D, 0, len(elements))
+
+	for _, element := range elements {
+		if element.Key() != field {
+			doc = append(doc, bson.E{Key: element.Key(), Value: element.Value()})
+		}
+	}
+
+	return bson.Marshal(doc)
+}
+
+// edgeKeys returns the keys of the value of field, one per element for
+// arrays, as $graphLookup matches them.
+func edgeKeys(raw bson.Raw, field string) []string {
+	value, err := raw.LookupErr(field)
+
+	if err != nil {
+		return nil
+	}
+
+	if value.Type != bson.TypeArray {
+		return []string{idKey(value)}
+	}
+
+	values, _ := value.Array().Values()
+	keys := make([]string, 0, len(values))
+
+	for _, item := range values {
+		keys = append(keys, idKey(item))
+	}
+
+	return keys
+}
+
+// findCycles walks the edges between the traversed documents depth first
+// and returns the cycle closed by every edge back onto the current path.
+func findCycles(order []string, docs map[string]bson.Raw, connectFrom string, connectTo string) [][]interface{} {
+	targets := map[string][]string{}
+
+	for _, key := range order {
+		for _, edge := range edgeKeys(docs[key], connectTo) {
+			targets[edge] = append(targets[edge], key)
+		}
+	}
+
+	const (
+		unvisited = iota
+		onPath
+		finished
+	)
+
+	state := map[string]int{}
+	path := []string{}
+	cycles := [][]interface{}{}
+
+	var walk func(key string)
+
+	walk = func(key string) {
+		state[key] = onPath
+		path = append(path, key)
+
+		for _, edge := range edgeKeys(docs[key], connectFrom) {
+			for _, next := range targets[edge] {
+				switch state[next] {
+				case unvisited:
+					walk(next)
+				case onPath:
+					cycle := []interface{}{}
+
+					for i := len(path) - 1; i >= 0; i-- {
+						if path[i] == next {
+							for _, member := range path[i:] {
+								var id interface{}
+								docs[member].Lookup("_id").Unmarshal(&id)
+								cycle = append(cycle, id)
+							}
+
+							break
+						}
+					}
+
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[key] = finished
+	}
+
+	for _, key := range order {
+		if state[key] == unvisited {
+			walk(key)
+		}
+	}
+
+	return cycles
+}
@@ -0,0 +1,31 @@
+package remongo
+
+// WithHint returns a copy of the repository that pins its queries to the
+// given index, either by name or by key specification.
+func (mr *MongoRepository[T]) WithHint(hint interface{}) IMongoRepository[T] {
+	clone := *mr
+	clone.hint = hint
+
+	return &clone
+}
+
+// WithQueryName returns a copy of the repository whose queries use the
+// hint registered under name in Hints and are tagged with that name.
+func (mr *MongoRepository[T]) WithQueryName(name string) IMongoRepository[T] {
+	clone := *mr
+	clone.queryName = name
+
+	return &clone
+}
+
+func (mr *MongoRepository[T]) queryHint() interface{} {
+	if mr.hint != nil {
+		return mr.hint
+	}
+
+	if mr.queryName != "" {
+		return mr.Hints[mr.queryName]
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package remongo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IDGenerator assigns _id values to inserted models whose _id is empty
+// and converts string ids received from callers into stored ids.
+type IDGenerator interface {
+	NewID() interface{}
+	ParseID(s string) (interface{}, error)
+}
+
+type ObjectIDGenerator struct{}
+
+func (ObjectIDGenerator) NewID() interface{} {
+	return primitive.NewObjectID()
+}
+
+func (ObjectIDGenerator) ParseID(s string) (interface{}, error) {
+	id, err := ParseID(s)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDGenerator produces 26 character, lexicographically sortable ULIDs.
+type ULIDGenerator struct{}
+
+func (ULIDGenerator) NewID() interface{} {
+	var id [16]byte
+
+	putMillis(id[:6], time.Now())
+	randomBytes(id[6:])
+
+	value := new(big.Int).SetBytes(id[:])
+	out := make([]byte, 26)
+
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockford[new(big.Int).And(value, big.NewInt(31)).Int64()]
+		value.Rsh(value, 5)
+	}
+
+	return string(out)
+}
+
+func (ULIDGenerator) ParseID(s string) (interface{}, error) {
+	s = strings.ToUpper(s)
+
+	if len(s) != 26 || s[0] > '7' || strings.Trim(s, crockford) != "" {
+		return nil, fmt.Errorf("%w: %q is not a ULID", ErrInvalidID, s)
+	}
+
+	return s, nil
+}
+
+// UUIDv7Generator produces time-ordered RFC 9562 version 7 UUIDs.
+type UUIDv7Generator struct{}
+
+func (UUIDv7Generator) NewID() interface{} {
+	var id [16]byte
+
+	putMillis(id[:6], time.Now())
+	randomBytes(id[6:])
+	id[6] = id[6]&0x0f | 0x70
+	id[8] = id[8]&0x3f | 0x80
+
+	h := hex.EncodeToString(id[:])
+
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+func (UUIDv7Generator) ParseID(s string) (interface{}, error) {
+	s = strings.ToLower(s)
+	raw := strings.ReplaceAll(s, "-", "")
+
+	if len(s) != 36 || len(raw) != 32 || s[14] != '7' {
+		return nil, fmt.Errorf("%w: %q is not a UUIDv7", ErrInvalidID, s)
+	}
+
+	if _, err := hex.DecodeString(raw); err != nil {
+		return nil, fmt.Errorf("%w: %q is not a UUIDv7", ErrInvalidID, s)
+	}
+
+	return s, nil
+}
+
+const (
+	base62      = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	ksuidEpoch  = 1400000000
+	ksuidLength = 27
+)
+
+// KSUIDGenerator produces 27 character KSUIDs ordered by creation second.
+type KSUIDGenerator struct{}
+
+func (KSUIDGenerator) NewID() interface{} {
+	var id [20]byte
+
+	binary.BigEndian.PutUint32(id[:4], uint32(time.Now().Unix()-ksuidEpoch))
+	randomBytes(id[4:])
+
+	value := new(big.Int).SetBytes(id[:])
+	out := []byte(strings.Repeat("0", ksuidLength))
+	base := big.NewInt(62)
+	mod := new(big.Int)
+
+	for i := ksuidLength - 1; i >= 0 && value.Sign() > 0; i-- {
+		value.DivMod(value, base, mod)
+		out[i] = base62[mod.Int64()]
+	}
+
+	return string(out)
+}
+
+func (KSUIDGenerator) ParseID(s string) (interface{}, error) {
+	if len(s) != ksuidLength || strings.Trim(s, base62) != "" {
+		return nil, fmt.Errorf("%w: %q is not a KSUID", ErrInvalidID, s)
+	}
+
+	return s, nil
+}
+
+func putMillis(b []byte, t time.Time) {
+	ms := uint64(t.UnixMilli())
+
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+}
+
+func randomBytes(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+}
+
+func (mr *MongoRepository[T]) idGenerator() IDGenerator {
+	if mr.IDGenerator != nil {
+		return mr.IDGenerator
+	}
+
+	return ObjectIDGenerator{}
+}
+
+// assignID sets a generated _id on models whose _id is empty. Without an
+// IDGenerator the server keeps assigning ObjectIDs.
+func (mr *MongoRepository[T]) assignID(model interface{}) {
+	if mr.IDGenerator != nil {
+		assignID(model, mr.IDGenerator)
+	}
+}
+
+func assignID(model interface{}, generator IDGenerator) {
+	v := reflect.ValueOf(model)
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.IsNil() {
+			return
+		}
+
+		key := reflect.ValueOf("_id").Convert(v.Type().Key())
+
+		if existing := v.MapIndex(key); !existing.IsValid() || existing.IsZero() {
+			id := reflect.ValueOf(generator.NewID())
+
+			if id.Type().AssignableTo(v.Type().Elem()) {
+				v.SetMapIndex(key, id)
+			}
+		}
+	case reflect.Struct:
+		for _, field := range bsonFields(v.Type()) {
+			if field.Name != "_id" {
+				continue
+			}
+
+			value, err := v.FieldByIndexErr(field.Index)
+
+			if err != nil || !value.CanSet() || !value.IsZero() {
+				return
+			}
+
+			id := reflect.ValueOf(generator.NewID())
+
+			if id.Type().AssignableTo(value.Type()) {
+				value.Set(id)
+			} else if id.Type().ConvertibleTo(value.Type()) && id.Kind() == value.Kind() {
+				value.Set(id.Convert(value.Type()))
+			}
+
+			return
+		}
+	}
+}
+
+// ByID returns the filter for id. Strings are parsed with the configured
+// IDGenerator, so hex strings become ObjectIDs by default.
+func (mr *MongoRepository[T]) ByID(id interface{}) (bson.M, error) {
+	parsed, err := mr.parseID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.M{"_id": parsed}, nil
+}
+
+func (mr *MongoRepository[T]) parseID(id interface{}) (interface{}, error) {
+	if s, ok := id.(string); ok {
+		return mr.idGenerator().ParseID(s)
+	}
+
+	return id, nil
+}
+
+// FindByID returns the document with id, or nil when it does not exist.
+func (mr *MongoRepository[T]) FindByID(ctx context.Context, id interface{}) (*T, error) {
+	filter, err := mr.ByID(id)
+
+	if err != nil {
+		return nil, mr.wrapError("FindByID", nil, err)
+	}
+
+	return mr.Query().Where(filter).One(ctx)
+}
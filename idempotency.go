@@ -0,0 +1,153 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IdempotencyCollection prefixes the collection of each repository that
+// stores one document per key, {_id, doc_id, created_at}, e.g.
+// remongo_idempotency_orders; the TTL index of EnsureIdempotencyIndexes
+// on created_at bounds how long keys are kept.
+const IdempotencyCollection = "remongo_idempotency"
+
+const codeIndexOptionsConflict = 85
+
+// idempotencyClaimTimeout is how long a key may wait for its document
+// before it counts as abandoned and is reclaimed.
+const idempotencyClaimTimeout = time.Minute
+
+// EnsureIdempotencyIndexes expires the keys of InsertIdempotent ttl after
+// they were first used, changing the expiry of an existing index.
+func (mr *MongoRepository[T]) EnsureIdempotencyIndexes(ctx context.Context, ttl time.Duration) error {
+	keys := mr.idempotencyKeys()
+	index := bson.D{{Key: "created_at", Value: 1}}
+
+	_, err := keys.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    index,
+		Options: options.Index().SetExpireAfterSeconds(int32(ttl.Seconds())),
+	})
+
+	var se mongo.ServerError
+
+	if !errors.As(err, &se) || !se.HasErrorCode(codeIndexOptionsConflict) {
+		return err
+	}
+
+	return keys.Database().RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: keys.Name()},
+		{Key: "index", Value: bson.D{
+			{Key: "keyPattern", Value: index},
+			{Key: "expireAfterSeconds", Value: int64(ttl.Seconds())},
+		}},
+	}).Err()
+}
+
+func (mr *MongoRepository[T]) idempotencyKeys() *mongo.Collection {
+	return mr.Database.Collection(IdempotencyCollection + "_" + mr.CollectionName())
+}
+
+// InsertIdempotent inserts model once per key. A retry with the same key
+// returns the document inserted first and false instead of failing with
+// a duplicate key error.
+func (mr *MongoRepository[T]) InsertIdempotent(ctx context.Context, key string, model *T) (*T, bool, error) {
+	keys := mr.idempotencyKeys()
+	keyID := mr.CollectionName() + ":" + key
+
+	existing, err := mr.idempotentDocument(ctx, keys, keyID)
+
+	if err != nil || existing != nil {
+		return existing, false, err
+	}
+
+	assignID(model, mr.idGenerator())
+
+	raw, err := mr.marshal(model)
+
+	if err != nil {
+		return nil, false, mr.wrapError("InsertIdempotent", nil, err)
+	}
+
+	id, err := raw.LookupErr("_id")
+
+	if err != nil {
+		return nil, false, mr.wrapError("InsertIdempotent", nil, fmt.Errorf("remongo: %T has no _id to record", model))
+	}
+
+	_, err = keys.InsertOne(ctx, bson.M{
+		"_id":        keyID,
+		"doc_id":     id,
+		"created_at": time.Now(),
+	})
+
+	if mongo.IsDuplicateKeyError(err) {
+		existing, err = mr.idempotentDocument(ctx, keys, keyID)
+
+		if err == nil && existing == nil {
+			err = ErrNotFound
+		}
+
+		return existing, false, mr.wrapError("InsertIdempotent", nil, err)
+	}
+
+	if err != nil {
+		return nil, false, mr.wrapError("InsertIdempotent", nil, err)
+	}
+
+	if err, _ = mr.WithContext(ctx).InsertOne(model); err != nil {
+		// Release the key so that the caller can retry; a key left behind
+		// is reclaimed once idempotencyClaimTimeout passed.
+		if _, releaseErr := keys.DeleteOne(ctx, bson.M{"_id": keyID}); releaseErr != nil {
+			mr.logger().Printf("remongo: releasing idempotency key %s failed: %v", keyID, releaseErr)
+		}
+
+		return nil, false, err
+	}
+
+	return model, true, nil
+}
+
+// idempotentDocument returns the document recorded for keyID, or nil
+// when the key is unused. A key whose document is still missing fails
+// with ErrNotFound while its insert may be in progress, and is reclaimed
+// as unused after that.
+func (mr *MongoRepository[T]) idempotentDocument(ctx context.Context, keys *mongo.Collection, keyID string) (*T, error) {
+	var record struct {
+		DocID     bson.RawValue `bson:"doc_id"`
+		CreatedAt time.Time     `bson:"created_at"`
+	}
+
+	err := keys.FindOne(ctx, bson.M{"_id": keyID}).Decode(&record)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, mr.wrapError("InsertIdempotent", nil, err)
+	}
+
+	doc, err := mr.Query().Where(bson.M{"_id": record.DocID}).One(ctx)
+
+	if err != nil || doc != nil {
+		return doc, err
+	}
+
+	if time.Since(record.CreatedAt) < idempotencyClaimTimeout {
+		return nil, mr.wrapError("InsertIdempotent", nil, fmt.Errorf("%w: key %s is still being inserted", ErrNotFound, keyID))
+	}
+
+	_, err = keys.DeleteOne(ctx, bson.M{"_id": keyID, "created_at": record.CreatedAt})
+
+	if err != nil {
+		return nil, mr.wrapError("InsertIdempotent", nil, err)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,75 @@
+package remongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type IndexAccesses struct {
+	Ops   int64     `bson:"ops"`
+	Since time.Time `bson:"since"`
+}
+
+type IndexStat struct {
+	Name     string        `bson:"name"`
+	Key      bson.D        `bson:"key"`
+	Host     string        `bson:"host"`
+	Shard    string        `bson:"shard,omitempty"`
+	Accesses IndexAccesses `bson:"accesses"`
+}
+
+// IndexStats reports per-index access counters of the collection. On
+// sharded clusters and replica sets each host reports separately.
+func (mr *MongoRepository[T]) IndexStats(ctx context.Context) ([]IndexStat, error) {
+	pipeline := mongo.Pipeline{{{Key: "$indexStats", Value: bson.M{}}}}
+
+	cursor, err := mr.collection(ctx).Aggregate(ctx, pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stats := []IndexStat{}
+
+	if err = cursor.All(ctx, &stats); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// UnusedIndexes returns the indexes that no host has used since the
+// given time. Indexes whose counters were reset after since are not
+// reported, because the observation window would be too short.
+func (mr *MongoRepository[T]) UnusedIndexes(ctx context.Context, since time.Time) ([]IndexStat, error) {
+	stats, err := mr.IndexStats(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	unused := []IndexStat{}
+
+	for _, stat := range stats {
+		if stat.Accesses.Ops > 0 || stat.Accesses.Since.After(since) || stat.Name == "_id_" {
+			used[stat.Name] = true
+		}
+	}
+
+	seen := map[string]bool{}
+
+	for _, stat := range stats {
+		if used[stat.Name] || seen[stat.Name] {
+			continue
+		}
+
+		seen[stat.Name] = true
+		unused = append(unused, stat)
+	}
+
+	return unused, nil
+}
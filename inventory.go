@@ -0,0 +1,45 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrInsufficient = errors.New("remongo: insufficient amount")
+
+// DecrementIfAtLeast atomically subtracts amount from field of the first
+// document matching filter, only if field holds at least amount, and
+// returns the updated document, e.g. to reserve stock. It fails with
+// ErrInsufficient when the value is too low, ErrNotFound when nothing
+// matches filter and ErrValidation when amount is not positive.
+func (mr *MongoRepository[T]) DecrementIfAtLeast(
+	ctx context.Context,
+	filter interface{},
+	field string,
+	amount int64,
+) (*T, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: decrement of %s by %d", ErrValidation, field, amount)
+	}
+
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	guarded := bson.D{{Key: "$and", Value: bson.A{query, bson.M{field: bson.M{"$gte": amount}}}}}
+
+	return mr.findOneAndUpdate(ctx, guarded, bson.M{"$inc": bson.M{field: -amount}}, func(ctx context.Context, scope interface{}) error {
+		err := mr.collection(ctx).FindOne(ctx, within(query, scope)).Err()
+
+		if err != nil {
+			return err
+		}
+
+		return fmt.Errorf("%w: %s is below %d", ErrInsufficient, field, amount)
+	})
+}
@@ -0,0 +1,139 @@
+package remongo
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// KeysetPage is one page of FindKeyset. Next is empty on the last page.
+type KeysetPage[T IMongoModel] struct {
+	Items []T
+	Next  string
+}
+
+type keysetCursor struct {
+	Value bson.RawValue `bson:"v"`
+	ID    bson.RawValue `bson:"id"`
+}
+
+// FindKeyset pages through the documents matching filter ordered by
+// sortField and then _id, so ties never skip or repeat documents. Pass
+// the Next of the previous page as after, or "" for the first page. A
+// limit below 1 fails with ErrValidation.
+// sortField should be set on every document: ranges over null match
+// nothing.
+func (mr *MongoRepository[T]) FindKeyset(
+	ctx context.Context,
+	filter interface{},
+	sortField string,
+	direction SortDirection,
+	limit int64,
+	after string,
+) (*KeysetPage[T], error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: keyset page limit must be positive, got %d", ErrValidation, limit)
+	}
+
+	if err := ValidatePath(mr.Model, sortField); err != nil {
+		return nil, err
+	}
+
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if after != "" {
+		cursor, err := decodeKeysetCursor(after)
+
+		if err != nil {
+			return nil, err
+		}
+
+		op := "$gt"
+
+		if direction == Desc {
+			op = "$lt"
+		}
+
+		query = bson.D{{Key: "$and", Value: bson.A{query, bson.M{"$or": bson.A{
+			bson.M{sortField: bson.M{op: cursor.Value}},
+			bson.M{sortField: cursor.Value, "_id": bson.M{op: cursor.ID}},
+		}}}}}
+	}
+
+	opts := options.Find().
+		SetSort(SortBy(sortField, direction).ThenBy("_id", direction).Doc()).
+		SetLimit(limit + 1)
+
+	var raws []bson.Raw
+
+	err = mr.runContext(ctx, "FindKeyset", query, nil, func(ctx context.Context, op *Operation) error {
+		cursor, err := mr.collection(ctx).Find(ctx, op.Filter, mr.findOptions(ctx, []*options.FindOptions{opts})...)
+
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &raws)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	page := &KeysetPage[T]{Items: make([]T, 0, len(raws))}
+
+	if int64(len(raws)) > limit {
+		raws = raws[:limit]
+		last := raws[len(raws)-1]
+
+		page.Next, err = encodeKeysetCursor(keysetCursor{
+			Value: last.Lookup(strings.Split(sortField, ".")...),
+			ID:    last.Lookup("_id"),
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, raw := range raws {
+		var model T
+
+		if err = mr.decode(ctx, raw, &model); err != nil {
+			return nil, mr.wrapError("FindKeyset", filter, err)
+		}
+
+		page.Items = append(page.Items, model)
+	}
+
+	return page, nil
+}
+
+func encodeKeysetCursor(cursor keysetCursor) (string, error) {
+	raw, err := bson.Marshal(cursor)
+
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeKeysetCursor(s string) (keysetCursor, error) {
+	cursor := keysetCursor{}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+
+	if err != nil {
+		return cursor, err
+	}
+
+	return cursor, bson.Unmarshal(raw, &cursor)
+}
@@ -0,0 +1,15 @@
+package remongo
+
+import "context"
+
+// FindLatest returns the matching document with the highest value of
+// byField (usually a timestamp), or nil when nothing matches.
+func (mr *MongoRepository[T]) FindLatest(ctx context.Context, filter interface{}, byField string) (*T, error) {
+	return mr.Query().Where(filter).Sort(SortBy(byField, Desc)).One(ctx)
+}
+
+// FindEarliest returns the matching document with the lowest value of
+// byField, or nil when nothing matches.
+func (mr *MongoRepository[T]) FindEarliest(ctx context.Context, filter interface{}, byField string) (*T, error) {
+	return mr.Query().Where(filter).Sort(SortBy(byField, Asc)).One(ctx)
+}
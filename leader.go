@@ -0,0 +1,76 @@
+package remongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const LeaderCollection = "remongo_leaders"
+
+// LeaderElector elects a single holder for a named role using a lease
+// document that expires unless it is renewed by its holder.
+type LeaderElector struct {
+	Database *mongo.Database
+	Name     string
+	ID       string
+	TTL      time.Duration
+}
+
+func InitLeaderElector(database *mongo.Database, name string, id string, ttl time.Duration) *LeaderElector {
+	return &LeaderElector{
+		Database: database,
+		Name:     name,
+		ID:       id,
+		TTL:      ttl,
+	}
+}
+
+func (le *LeaderElector) GetCollection() *mongo.Collection {
+	return le.Database.Collection(LeaderCollection)
+}
+
+// TryAcquire claims or renews the lease and reports whether this
+// instance is the leader until the lease expires.
+func (le *LeaderElector) TryAcquire(ctx context.Context) (bool, error) {
+	now := time.Now()
+
+	filter := bson.M{
+		"_id": le.Name,
+		"$or": bson.A{
+			bson.M{"holder": le.ID},
+			bson.M{"expires_at": bson.M{"$lte": now}},
+		},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"holder":     le.ID,
+			"expires_at": now.Add(le.TTL),
+		},
+	}
+
+	_, err := le.GetCollection().
+		UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Release gives up the lease if it is held by this instance.
+func (le *LeaderElector) Release(ctx context.Context) error {
+	_, err := le.GetCollection().
+		DeleteOne(ctx, bson.M{"_id": le.Name, "holder": le.ID})
+
+	return err
+}
@@ -0,0 +1,86 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	LeaseHolderField  = "_lease_holder"
+	LeaseExpiresField = "_lease_expires_at"
+)
+
+var (
+	ErrLeaseHeld = errors.New("remongo: document leased by another holder")
+	ErrLeaseLost = errors.New("remongo: lease no longer held")
+)
+
+// LeaseState can be embedded with `bson:",inline"` by models that want
+// to read their lease.
+type LeaseState struct {
+	Holder    string    `bson:"_lease_holder,omitempty"`
+	ExpiresAt time.Time `bson:"_lease_expires_at,omitempty"`
+}
+
+// Lease claims the first document matching filter for holder until ttl
+// from now, when it is unclaimed, expired or already held by holder, and
+// returns it. It fails with ErrLeaseHeld when every match is held by
+// someone else and ErrNotFound when nothing matches. Expiry uses the
+// clock of the caller, so holders need roughly synchronized clocks.
+func (mr *MongoRepository[T]) Lease(ctx context.Context, filter interface{}, holder string, ttl time.Duration) (*T, error) {
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	claimable := bson.D{{Key: "$and", Value: bson.A{query, bson.M{"$or": bson.A{
+		bson.M{LeaseHolderField: bson.M{"$in": bson.A{nil, holder}}},
+		bson.M{LeaseExpiresField: bson.M{"$lte": now}},
+	}}}}}
+
+	update := bson.M{"$set": bson.M{LeaseHolderField: holder, LeaseExpiresField: now.Add(ttl)}}
+
+	return mr.findOneAndUpdate(ctx, claimable, update, func(ctx context.Context, scope interface{}) error {
+		if err := mr.collection(ctx).FindOne(ctx, within(query, scope)).Err(); err != nil {
+			return err
+		}
+
+		return ErrLeaseHeld
+	})
+}
+
+// ExtendLease pushes the expiry of a lease holder still holds to ttl from
+// now, failing with ErrLeaseLost once it expired or was taken over.
+func (mr *MongoRepository[T]) ExtendLease(ctx context.Context, id interface{}, holder string, ttl time.Duration) (*T, error) {
+	now := time.Now()
+
+	held := bson.D{
+		{Key: "_id", Value: id},
+		{Key: LeaseHolderField, Value: holder},
+		{Key: LeaseExpiresField, Value: bson.M{"$gt": now}},
+	}
+
+	return mr.findOneAndUpdate(ctx, held, bson.M{"$set": bson.M{LeaseExpiresField: now.Add(ttl)}}, func(ctx context.Context, scope interface{}) error {
+		return fmt.Errorf("%w: %v by %s", ErrLeaseLost, id, holder)
+	})
+}
+
+// ReleaseLease frees the document if holder still holds it, failing with
+// ErrLeaseLost otherwise. Like Lease it writes directly, never through
+// write-behind.
+func (mr *MongoRepository[T]) ReleaseLease(ctx context.Context, id interface{}, holder string) error {
+	held := bson.D{{Key: "_id", Value: id}, {Key: LeaseHolderField, Value: holder}}
+
+	_, err := mr.findOneAndUpdate(ctx, held, bson.M{"$unset": bson.M{LeaseHolderField: "", LeaseExpiresField: ""}}, func(ctx context.Context, scope interface{}) error {
+		return fmt.Errorf("%w: %v by %s", ErrLeaseLost, id, holder)
+	})
+
+	return err
+}
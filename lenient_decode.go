@@ -0,0 +1,112 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// DecodeFieldError is one field that could not be decoded.
+type DecodeFieldError struct {
+	Path string
+	Err  error
+}
+
+// DecodeReport lists the fields of one document that were left zero
+// because they did not decode.
+type DecodeReport struct {
+	Collection string
+	ID         bson.RawValue
+	Fields     []DecodeFieldError
+}
+
+func (r DecodeReport) String() string {
+	parts := make([]string, 0, len(r.Fields))
+
+	for _, field := range r.Fields {
+		parts = append(parts, field.Path+": "+field.Err.Error())
+	}
+
+	return fmt.Sprintf("remongo: %s %v decoded partially: %s", r.Collection, r.ID, strings.Join(parts, "; "))
+}
+
+// WithLenientDecode makes reads load every field that decodes and pass
+// the failures of each document to report, e.g. a string where an int is
+// expected. A nil report logs them.
+func WithLenientDecode(report func(ctx context.Context, report DecodeReport)) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.decodeMode = DecodeLenient
+		c.decodeReport = report
+	}
+}
+
+func (mr *MongoRepository[T]) unmarshalLenient(ctx context.Context, raw bson.Raw, model *T) error {
+	if err := mr.unmarshal(raw, model); err == nil {
+		return nil
+	}
+
+	report := DecodeReport{Collection: mr.CollectionName(), ID: raw.Lookup("_id")}
+	mr.decodeFields(raw, reflect.ValueOf(model).Elem(), "", &report)
+
+	if mr.OnDecodeReport != nil {
+		mr.OnDecodeReport(ctx, report)
+	} else {
+		mr.logger().Printf("%v", report)
+	}
+
+	return nil
+}
+
+// decodeFields decodes the elements of raw into v one at a time,
+// descending into embedded documents whose field is a struct.
+func (mr *MongoRepository[T]) decodeFields(raw bson.Raw, v reflect.Value, prefix string, report *DecodeReport) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		v = v.Elem()
+	}
+
+	fields := map[string]bsonField{}
+
+	for _, field := range bsonFields(v.Type()) {
+		fields[field.Name] = field
+	}
+
+	elements, err := raw.Elements()
+
+	if err != nil {
+		report.Fields = append(report.Fields, DecodeFieldError{Path: strings.TrimSuffix(prefix, "."), Err: err})
+
+		return
+	}
+
+	for _, element := range elements {
+		single := bson.Raw(bsoncore.BuildDocument(nil, element))
+		err := mr.unmarshal(single, v.Addr().Interface())
+
+		if err == nil {
+			continue
+		}
+
+		path := prefix + element.Key()
+		field, ok := fields[element.Key()]
+
+		if ok && element.Value().Type == bsontype.EmbeddedDocument && indirectType(field.Type).Kind() == reflect.Struct {
+			if target, err := v.FieldByIndexErr(field.Index); err == nil {
+				target.Set(reflect.Zero(target.Type()))
+				mr.decodeFields(element.Value().Document(), target, path+".", report)
+
+				continue
+			}
+		}
+
+		report.Fields = append(report.Fields, DecodeFieldError{Path: path, Err: err})
+	}
+}
@@ -0,0 +1,202 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type LoaderOptions struct {
+	// Wait is how long a batch collects ids before it is fetched.
+	Wait time.Duration
+	// MaxBatch fetches a batch early once it holds this many ids.
+	MaxBatch int
+}
+
+type loaderResult[T IMongoModel] struct {
+	done  chan struct{}
+	model *T
+	err   error
+}
+
+type loaderBatch[T IMongoModel] struct {
+	ids     []interface{}
+	results []*loaderResult[T]
+	timer   *time.Timer
+}
+
+// Loader batches the ids requested within Wait into one FindByIDs call
+// and caches every result. Create one per request: it never expires its
+// cache. Ids must be comparable values such as strings or ObjectIDs.
+type Loader[T IMongoModel] struct {
+	repo     IReadRepository[T]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[interface{}]*loaderResult[T]
+	batch *loaderBatch[T]
+}
+
+func NewLoader[T IMongoModel](repo IReadRepository[T], opts ...*LoaderOptions) *Loader[T] {
+	loader := &Loader[T]{
+		repo:     repo,
+		wait:     time.Millisecond * 2,
+		maxBatch: 100,
+		cache:    map[interface{}]*loaderResult[T]{},
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		if opt.Wait > 0 {
+			loader.wait = opt.Wait
+		}
+
+		if opt.MaxBatch > 0 {
+			loader.maxBatch = opt.MaxBatch
+		}
+	}
+
+	return loader
+}
+
+// Load returns the document for id, or nil when it does not exist.
+func (l *Loader[T]) Load(ctx context.Context, id interface{}) (*T, error) {
+	l.mu.Lock()
+
+	result, ok := l.cache[id]
+
+	if !ok {
+		result = &loaderResult[T]{done: make(chan struct{})}
+		l.cache[id] = result
+		l.enqueue(ctx, id, result)
+	}
+
+	l.mu.Unlock()
+
+	select {
+	case <-result.done:
+		return result.model, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *Loader[T]) LoadMany(ctx context.Context, ids []interface{}) ([]*T, []error) {
+	models := make([]*T, len(ids))
+	errs := make([]error, len(ids))
+
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		wg.Add(1)
+
+		go func(i int, id interface{}) {
+			defer wg.Done()
+
+			models[i], errs[i] = l.Load(ctx, id)
+		}(i, id)
+	}
+
+	wg.Wait()
+
+	return models, errs
+}
+
+// Prime caches model for id without fetching it.
+func (l *Loader[T]) Prime(id interface{}, model *T) {
+	result := &loaderResult[T]{done: make(chan struct{}), model: model}
+	close(result.done)
+
+	l.mu.Lock()
+	l.cache[id] = result
+	l.mu.Unlock()
+}
+
+// Clear drops id from the cache, e.g. after it was written.
+func (l *Loader[T]) Clear(id interface{}) {
+	l.mu.Lock()
+	delete(l.cache, id)
+	l.mu.Unlock()
+}
+
+// enqueue must be called with l.mu held.
+func (l *Loader[T]) enqueue(ctx context.Context, id interface{}, result *loaderResult[T]) {
+	if l.batch == nil {
+		batch := &loaderBatch[T]{}
+		batch.timer = time.AfterFunc(l.wait, func() {
+			l.mu.Lock()
+
+			if l.batch != batch {
+				l.mu.Unlock()
+
+				return
+			}
+
+			l.batch = nil
+			l.mu.Unlock()
+
+			l.fetch(context.WithoutCancel(ctx), batch)
+		})
+		l.batch = batch
+	}
+
+	l.batch.ids = append(l.batch.ids, id)
+	l.batch.results = append(l.batch.results, result)
+
+	if len(l.batch.ids) >= l.maxBatch {
+		batch := l.batch
+		l.batch = nil
+		batch.timer.Stop()
+
+		go l.fetch(context.WithoutCancel(ctx), batch)
+	}
+}
+
+func (l *Loader[T]) fetch(ctx context.Context, batch *loaderBatch[T]) {
+	models, err := l.repo.FindByIDs(ctx, batch.ids)
+
+	var missing *MissingIDsError
+
+	if err != nil && !errors.As(err, &missing) {
+		l.mu.Lock()
+
+		for i, id := range batch.ids {
+			if l.cache[id] == batch.results[i] {
+				delete(l.cache, id)
+			}
+		}
+
+		l.mu.Unlock()
+
+		for _, result := range batch.results {
+			result.err = err
+			close(result.done)
+		}
+
+		return
+	}
+
+	absent := map[interface{}]bool{}
+
+	if missing != nil {
+		for _, id := range missing.IDs {
+			absent[id] = true
+		}
+	}
+
+	next := 0
+
+	for i, id := range batch.ids {
+		if !absent[id] && next < len(models) {
+			batch.results[i].model = &models[next]
+			next++
+		}
+
+		close(batch.results[i].done)
+	}
+}
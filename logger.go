@@ -0,0 +1,16 @@
+package remongo
+
+import "log"
+
+// Logger is satisfied by *log.Logger and most structured logger adapters.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+func (mr *MongoRepository[T]) logger() Logger {
+	if mr.Logger != nil {
+		return mr.Logger
+	}
+
+	return log.Default()
+}
@@ -0,0 +1,106 @@
+package remongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MergeOptions configures the $merge stage. Empty fields keep the server
+// defaults: match on _id, merge matched documents, insert the others.
+type MergeOptions struct {
+	Database       string
+	On             []string
+	WhenMatched    interface{}
+	WhenNotMatched string
+}
+
+func mergeStage(target string, opts *MergeOptions) bson.D {
+	into := interface{}(target)
+	spec := bson.D{}
+
+	if opts != nil {
+		if opts.Database != "" {
+			into = bson.M{"db": opts.Database, "coll": target}
+		}
+
+		if len(opts.On) > 0 {
+			spec = append(spec, bson.E{Key: "on", Value: opts.On})
+		}
+
+		if opts.WhenMatched != nil {
+			spec = append(spec, bson.E{Key: "whenMatched", Value: opts.WhenMatched})
+		}
+
+		if opts.WhenNotMatched != "" {
+			spec = append(spec, bson.E{Key: "whenNotMatched", Value: opts.WhenNotMatched})
+		}
+	}
+
+	return bson.D{{Key: "$merge", Value: append(bson.D{{Key: "into", Value: into}}, spec...)}}
+}
+
+// MergeInto runs pipeline over the repository collection and merges its
+// output into targetCollection.
+func (mr *MongoRepository[T]) MergeInto(
+	ctx context.Context,
+	pipeline interface{},
+	targetCollection string,
+	mergeOpts *MergeOptions,
+) error {
+	stages := append(toPipeline(pipeline), mergeStage(targetCollection, mergeOpts))
+
+	err := mr.runContext(ctx, "MergeInto", nil, nil, func(ctx context.Context, op *Operation) error {
+		cursor, err := mr.collection(ctx).Aggregate(ctx, stages, mr.aggregateOptions(ctx, nil)...)
+
+		if err != nil {
+			return err
+		}
+
+		return cursor.Close(ctx)
+	})
+
+	// Repositories sharing the cache may read the target.
+	if mr.Cache != nil {
+		mr.Cache.InvalidateTags(collectionTag(targetCollection))
+	}
+
+	return err
+}
+
+// MaterializedView is an aggregation whose output is stored in Target.
+// Refresh replaces Target with $out, or merges into it when Merge is set.
+type MaterializedView struct {
+	Source   *mongo.Collection
+	Pipeline mongo.Pipeline
+	Target   string
+	Merge    *MergeOptions
+}
+
+func InitMaterializedView(source ChangeSource, target string, pipeline mongo.Pipeline) *MaterializedView {
+	return &MaterializedView{
+		Source:   source.GetCollection(),
+		Pipeline: pipeline,
+		Target:   target,
+	}
+}
+
+func (mv *MaterializedView) Refresh(ctx context.Context) error {
+	stage := bson.D{{Key: "$out", Value: mv.Target}}
+
+	if mv.Merge != nil {
+		stage = mergeStage(mv.Target, mv.Merge)
+	}
+
+	pipeline := append(append(mongo.Pipeline{}, mv.Pipeline...), stage)
+
+	cursor, err := mv.Source.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+
+	if err != nil {
+		return err
+	}
+
+	return cursor.Close(ctx)
+}
@@ -0,0 +1,13 @@
+package remongo
+
+import "time"
+
+// Metric is a single measurement reported by background jobs.
+type Metric struct {
+	Name     string
+	Duration time.Duration
+	Count    int64
+	Err      error
+}
+
+type MetricsHook func(metric Metric)
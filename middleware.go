@@ -0,0 +1,173 @@
+package remongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Operation describes one repository call. Middleware may rewrite Filter
+// and Update before passing the call on.
+type Operation struct {
+	Name       string
+	Collection string
+	Filter     interface{}
+	Update     interface{}
+
+	written bson.Raw
+}
+
+var writeOperations = map[string]bool{
+	"InsertOne":        true,
+	"InsertMany":       true,
+	"ReplaceOne":       true,
+	"UpdateOne":        true,
+	"UpdateMany":       true,
+	"DeleteOne":        true,
+	"DeleteMany":       true,
+	"RunCommand":       true,
+	"FindOneAndUpdate": true,
+	"BulkWrite":        true,
+	"UpsertMany":       true,
+	"ArchiveMany":      true,
+	"Anonymize":        true,
+	"RestoreFromTrash": true,
+	"PurgeTrash":       true,
+	"RetentionBatch":   true,
+	"BulkUpsertStream": true,
+	"Backfill":         true,
+	"FinalizeRenames":  true,
+	"MergeInto":        true,
+	"Revert":           true,
+}
+
+type Handler func(ctx context.Context, op *Operation) error
+
+// Middleware wraps every CRUD call of the repository. The first
+// middleware registered is the outermost.
+type Middleware func(next Handler) Handler
+
+// Hooks are optional callbacks around repository operations. Except for
+// AfterOperation they run inside the middleware chain. BeforeWrite
+// receives the model about to be inserted or replaced and AfterRead every
+// decoded model; both may modify it in place.
+type Hooks struct {
+	BeforeOperation func(ctx context.Context, op *Operation) error
+	AfterOperation  func(ctx context.Context, op *Operation, err error)
+	BeforeWrite     func(ctx context.Context, model interface{}) error
+	AfterRead       func(ctx context.Context, model interface{}) error
+}
+
+// run executes fn as the named operation through the timeout, hooks and
+// middleware of the repository, and wraps the error it returns.
+func (mr *MongoRepository[T]) run(name string, filter interface{}, update interface{}, fn Handler) error {
+	done, err := beginOperation()
+
+	if err != nil {
+		return mr.wrapError(name, filter, err)
+	}
+
+	defer done()
+
+	ctx, cancel := mr.operationContext()
+	defer cancel()
+
+	op := &Operation{
+		Name:       name,
+		Collection: mr.CollectionName(),
+		Filter:     filter,
+		Update:     mr.renameUpdate(update),
+	}
+
+	handler := func(ctx context.Context, op *Operation) error {
+		if err := mr.applyScopes(ctx, op); err != nil {
+			return err
+		}
+
+		if mr.Authorizer != nil {
+			if err := mr.Authorizer.Authorize(ctx, op); err != nil {
+				return err
+			}
+		}
+
+		for _, hooks := range mr.Hooks {
+			if hooks.BeforeOperation != nil {
+				if err := hooks.BeforeOperation(ctx, op); err != nil {
+					return err
+				}
+			}
+		}
+
+		return fn(ctx, op)
+	}
+
+	for i := len(mr.Middleware) - 1; i >= 0; i-- {
+		handler = mr.Middleware[i](handler)
+	}
+
+	ctx, err = mr.checkStaleness(ctx, op)
+
+	if err == nil {
+		err = handler(ctx, op)
+	}
+
+	// Failed writes may still have been applied in part.
+	if writeOperations[op.Name] {
+		mr.invalidateCache(op)
+
+		if err == nil {
+			mr.cacheWritten(ctx, op)
+		}
+	}
+
+	err = mr.wrapError(op.Name, op.Filter, err)
+
+	for _, hooks := range mr.Hooks {
+		if hooks.AfterOperation != nil {
+			hooks.AfterOperation(ctx, op, err)
+		}
+	}
+
+	return err
+}
+
+// runContext is run for the methods taking a ctx rather than a
+// ForRequest copy.
+func (mr *MongoRepository[T]) runContext(ctx context.Context, name string, filter interface{}, update interface{}, fn Handler) error {
+	clone := *mr
+	clone.ctx = ctx
+
+	return clone.run(name, filter, update, fn)
+}
+
+func (mr *MongoRepository[T]) operationContext() (context.Context, context.CancelFunc) {
+	if mr.Timeout > 0 {
+		return context.WithTimeout(mr.context(), mr.Timeout)
+	}
+
+	return context.WithCancel(mr.context())
+}
+
+func (mr *MongoRepository[T]) beforeWrite(ctx context.Context, model interface{}) error {
+	for _, hooks := range mr.Hooks {
+		if hooks.BeforeWrite != nil {
+			if err := hooks.BeforeWrite(ctx, model); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (mr *MongoRepository[T]) afterRead(ctx context.Context, model interface{}) error {
+	for _, hooks := range mr.Hooks {
+		if hooks.AfterRead != nil {
+			if err := hooks.AfterRead(ctx, model); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
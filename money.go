@@ -0,0 +1,194 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Money is an exact decimal amount stored as Decimal128. The zero value
+// is 0.
+type Money struct {
+	coef *big.Int
+	exp  int
+}
+
+func ParseMoney(s string) (Money, error) {
+	d, err := primitive.ParseDecimal128(s)
+
+	if err != nil {
+		return Money{}, fmt.Errorf("remongo: invalid amount %q: %w", s, err)
+	}
+
+	return MoneyFromDecimal128(d)
+}
+
+func MustMoney(s string) Money {
+	m, err := ParseMoney(s)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// MoneyFromMinor builds an amount from minor units, e.g. 1999 cents with
+// scale 2 is 19.99.
+func MoneyFromMinor(units int64, scale int) Money {
+	return Money{coef: big.NewInt(units), exp: -scale}
+}
+
+func MoneyFromDecimal128(d primitive.Decimal128) (Money, error) {
+	coef, exp, err := d.BigInt()
+
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{coef: coef, exp: exp}, nil
+}
+
+func (m Money) coefficient() *big.Int {
+	if m.coef == nil {
+		return new(big.Int)
+	}
+
+	return m.coef
+}
+
+// align returns both coefficients at the smaller of the two exponents.
+func align(a Money, b Money) (*big.Int, *big.Int, int) {
+	ca, cb := new(big.Int).Set(a.coefficient()), new(big.Int).Set(b.coefficient())
+	exp := a.exp
+
+	switch {
+	case a.exp > b.exp:
+		ca.Mul(ca, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(a.exp-b.exp)), nil))
+		exp = b.exp
+	case b.exp > a.exp:
+		cb.Mul(cb, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(b.exp-a.exp)), nil))
+	}
+
+	return ca, cb, exp
+}
+
+func (m Money) Add(other Money) Money {
+	a, b, exp := align(m, other)
+
+	return Money{coef: a.Add(a, b), exp: exp}
+}
+
+func (m Money) Sub(other Money) Money {
+	a, b, exp := align(m, other)
+
+	return Money{coef: a.Sub(a, b), exp: exp}
+}
+
+func (m Money) Neg() Money {
+	return Money{coef: new(big.Int).Neg(m.coefficient()), exp: m.exp}
+}
+
+func (m Money) Cmp(other Money) int {
+	a, b, _ := align(m, other)
+
+	return a.Cmp(b)
+}
+
+func (m Money) IsZero() bool {
+	return m.coefficient().Sign() == 0
+}
+
+func (m Money) Decimal128() (primitive.Decimal128, error) {
+	d, ok := primitive.ParseDecimal128FromBigInt(m.coefficient(), m.exp)
+
+	if !ok {
+		return primitive.Decimal128{}, fmt.Errorf("remongo: amount %s overflows decimal128", m.coefficient())
+	}
+
+	return d, nil
+}
+
+func (m Money) String() string {
+	d, err := m.Decimal128()
+
+	if err != nil {
+		return "NaN"
+	}
+
+	return d.String()
+}
+
+func (m Money) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	d, err := m.Decimal128()
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return bson.MarshalValue(d)
+}
+
+func (m *Money) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t == bson.TypeNull || t == bson.TypeUndefined {
+		*m = Money{}
+
+		return nil
+	}
+
+	if t != bson.TypeDecimal128 {
+		return fmt.Errorf("remongo: cannot decode %s into Money", t)
+	}
+
+	parsed, err := MoneyFromDecimal128(bson.RawValue{Type: t, Value: data}.Decimal128())
+
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+}
+
+// IncMoney is an $inc update adding amount to field exactly on the
+// server; pass a negative amount to decrement.
+func IncMoney(field string, amount Money) bson.M {
+	return bson.M{"$inc": bson.M{field: amount}}
+}
+
+// SumMoney totals field over the documents matching filter as Decimal128,
+// converting numeric fields that are not yet stored as decimals.
+func (mr *MongoRepository[T]) SumMoney(ctx context.Context, field string, filter interface{}) (Money, error) {
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return Money{}, err
+	}
+
+	var rows []struct {
+		Total Money `bson:"total"`
+	}
+
+	err = mr.aggregateMatching(ctx, "SumMoney", query, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"total": bson.M{"$sum": bson.M{"$toDecimal": "$" + field}},
+		}}},
+	}, &rows)
+
+	if err != nil {
+		return Money{}, err
+	}
+
+	if len(rows) == 0 {
+		return Money{}, nil
+	}
+
+	return rows[0].Total, nil
+}
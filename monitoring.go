@@ -0,0 +1,114 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ClientMonitor forwards the driver's command, pool and server events to
+// a MetricsHook and logs the ones that point at trouble: cleared pools,
+// failed checkouts, failed heartbeats and server state changes. Connect
+// with its ClientOptions, e.g.
+//
+//	mongo.Connect(ctx, options.Client().ApplyURI(uri), monitor.ClientOptions())
+type ClientMonitor struct {
+	Logger  Logger
+	Metrics MetricsHook
+}
+
+func NewClientMonitor(logger Logger, metrics MetricsHook) *ClientMonitor {
+	return &ClientMonitor{Logger: logger, Metrics: metrics}
+}
+
+func (m *ClientMonitor) ClientOptions() *options.ClientOptions {
+	return options.Client().
+		SetMonitor(m.CommandMonitor()).
+		SetPoolMonitor(m.PoolMonitor()).
+		SetServerMonitor(m.ServerMonitor())
+}
+
+// CommandMonitor reports every command as "mongo.command.<name>" with
+// its duration.
+func (m *ClientMonitor) CommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			m.report(Metric{Name: "mongo.command." + e.CommandName, Duration: e.Duration, Count: 1})
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			m.report(Metric{
+				Name:     "mongo.command." + e.CommandName,
+				Duration: e.Duration,
+				Count:    1,
+				Err:      errors.New(e.Failure),
+			})
+		},
+	}
+}
+
+// PoolMonitor reports checkouts, with the time spent waiting, and
+// connections opened and closed, so connection storms show up as spikes.
+func (m *ClientMonitor) PoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			switch e.Type {
+			case event.GetSucceeded:
+				m.report(Metric{Name: "mongo.pool.checkout", Duration: e.Duration, Count: 1})
+			case event.GetFailed:
+				m.report(Metric{Name: "mongo.pool.checkout", Duration: e.Duration, Count: 1, Err: poolError(e)})
+				m.logf("remongo: connection checkout from %s failed: %s", e.Address, e.Reason)
+			case event.ConnectionCreated:
+				m.report(Metric{Name: "mongo.pool.opened", Count: 1})
+			case event.ConnectionClosed:
+				m.report(Metric{Name: "mongo.pool.closed", Count: 1})
+			case event.PoolCleared:
+				m.report(Metric{Name: "mongo.pool.cleared", Count: 1, Err: e.Error})
+				m.logf("remongo: connection pool for %s cleared: %v", e.Address, e.Error)
+			}
+		},
+	}
+}
+
+// ServerMonitor reports heartbeats and logs servers changing kind, which
+// is how elections and failovers appear to the client.
+func (m *ClientMonitor) ServerMonitor() *event.ServerMonitor {
+	return &event.ServerMonitor{
+		ServerHeartbeatSucceeded: func(e *event.ServerHeartbeatSucceededEvent) {
+			m.report(Metric{Name: "mongo.heartbeat", Duration: e.Duration, Count: 1})
+		},
+		ServerHeartbeatFailed: func(e *event.ServerHeartbeatFailedEvent) {
+			m.report(Metric{Name: "mongo.heartbeat", Duration: e.Duration, Count: 1, Err: e.Failure})
+			m.logf("remongo: heartbeat to %s failed: %v", e.ConnectionID, e.Failure)
+		},
+		ServerDescriptionChanged: func(e *event.ServerDescriptionChangedEvent) {
+			previous, current := e.PreviousDescription.Kind, e.NewDescription.Kind
+
+			if previous != current {
+				m.report(Metric{Name: "mongo.server.changed", Count: 1})
+				m.logf("remongo: server %s changed from %s to %s", e.Address, previous, current)
+			}
+		},
+	}
+}
+
+func (m *ClientMonitor) report(metric Metric) {
+	if m.Metrics != nil {
+		m.Metrics(metric)
+	}
+}
+
+func (m *ClientMonitor) logf(format string, v ...interface{}) {
+	if m.Logger != nil {
+		m.Logger.Printf(format, v...)
+	}
+}
+
+func poolError(e *event.PoolEvent) error {
+	if e.Error != nil {
+		return e.Error
+	}
+
+	return errors.New(e.Reason)
+}
@@ -0,0 +1,127 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize applies the `normalize:"..."` struct tags of model in place.
+// The rules are trim, lower, upper, nfc and nfkc, applied in tag order
+// to string fields and to the elements of string slices and maps. Nested
+// structs are normalized as well.
+func Normalize(model interface{}) error {
+	v := reflect.ValueOf(model)
+
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("remongo: Normalize needs a non-nil pointer, got %T", model)
+	}
+
+	return normalizeValue(v.Elem(), nil)
+}
+
+// NormalizeHooks runs Normalize on every model before it is written:
+//
+//	repo := remongo.NewRepository[User](db, remongo.WithHooks(remongo.NormalizeHooks()))
+func NormalizeHooks() Hooks {
+	return Hooks{
+		BeforeWrite: func(ctx context.Context, model interface{}) error {
+			if reflect.ValueOf(model).Kind() != reflect.Ptr {
+				return nil
+			}
+
+			return Normalize(model)
+		},
+	}
+}
+
+func normalizeValue(v reflect.Value, rules []string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+
+		return normalizeValue(v.Elem(), rules)
+	case reflect.String:
+		if len(rules) > 0 && v.CanSet() {
+			normalized, err := normalizeString(v.String(), rules)
+
+			if err != nil {
+				return err
+			}
+
+			v.SetString(normalized)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := normalizeValue(v.Index(i), rules); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if len(rules) == 0 || v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+
+		iter := v.MapRange()
+
+		for iter.Next() {
+			normalized, err := normalizeString(iter.Value().String(), rules)
+
+			if err != nil {
+				return err
+			}
+
+			v.SetMapIndex(iter.Key(), reflect.ValueOf(normalized).Convert(v.Type().Elem()))
+		}
+	case reflect.Struct:
+		if v.Type().PkgPath() == "time" {
+			return nil
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+
+			if !field.IsExported() {
+				continue
+			}
+
+			var fieldRules []string
+
+			if tag := field.Tag.Get("normalize"); tag != "" && tag != "-" {
+				fieldRules = strings.Split(tag, ",")
+			}
+
+			if err := normalizeValue(v.Field(i), fieldRules); err != nil {
+				return fmt.Errorf("%s: %w", field.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func normalizeString(s string, rules []string) (string, error) {
+	for _, rule := range rules {
+		switch strings.TrimSpace(rule) {
+		case "trim":
+			s = strings.TrimSpace(s)
+		case "lower":
+			s = strings.ToLower(s)
+		case "upper":
+			s = strings.ToUpper(s)
+		case "nfc":
+			s = norm.NFC.String(s)
+		case "nfkc":
+			s = norm.NFKC.String(s)
+		default:
+			return "", fmt.Errorf("remongo: unknown normalize rule %q", rule)
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,85 @@
+package remongo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidID = errors.New("remongo: invalid id")
+
+// ParseID parses a hex ObjectID and explains what is wrong with s when
+// it is not one.
+func ParseID(s string) (primitive.ObjectID, error) {
+	trimmed := strings.TrimSpace(s)
+
+	switch {
+	case trimmed == "":
+		return primitive.NilObjectID, fmt.Errorf("%w: empty string", ErrInvalidID)
+	case len(trimmed) != 24:
+		return primitive.NilObjectID, fmt.Errorf("%w: %q has %d characters, want 24", ErrInvalidID, s, len(trimmed))
+	}
+
+	id, err := primitive.ObjectIDFromHex(trimmed)
+
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: %q is not hexadecimal", ErrInvalidID, s)
+	}
+
+	return id, nil
+}
+
+func MustID(s string) primitive.ObjectID {
+	id, err := ParseID(s)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+func IDsToStrings(ids []primitive.ObjectID) []string {
+	out := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		out = append(out, id.Hex())
+	}
+
+	return out
+}
+
+// StringsToIDs parses every string, failing on the first invalid one.
+func StringsToIDs(values []string) ([]primitive.ObjectID, error) {
+	out := make([]primitive.ObjectID, 0, len(values))
+
+	for _, value := range values {
+		id, err := ParseID(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, id)
+	}
+
+	return out, nil
+}
+
+func CreationTime(id primitive.ObjectID) time.Time {
+	return id.Timestamp()
+}
+
+// NewIDAt returns the smallest ObjectID created at t, for range queries
+// such as {_id: {$gte: NewIDAt(from), $lt: NewIDAt(to)}}.
+func NewIDAt(t time.Time) primitive.ObjectID {
+	var id primitive.ObjectID
+
+	binary.BigEndian.PutUint32(id[0:4], uint32(t.Unix()))
+
+	return id
+}
@@ -0,0 +1,115 @@
+package remongo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// OperationError records which repository call failed. Filter values
+// are redacted so the message is safe to log.
+type OperationError struct {
+	Op         string
+	Collection string
+	Filter     string
+	Err        error
+}
+
+func (e *OperationError) Error() string {
+	if e.Filter != "" {
+		return fmt.Sprintf("remongo: %s on %s with filter %s: %v", e.Op, e.Collection, e.Filter, e.Err)
+	}
+
+	return fmt.Sprintf("remongo: %s on %s: %v", e.Op, e.Collection, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+func (mr *MongoRepository[T]) wrapError(op string, filter interface{}, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(*OperationError); ok {
+		return err
+	}
+
+	return &OperationError{
+		Op:         op,
+		Collection: mr.CollectionName(),
+		Filter:     RedactFilter(filter),
+		Err:        translateError(err),
+	}
+}
+
+// RedactFilter renders the shape of a filter with every value replaced
+// by "?", e.g. {age: {$gt: ?}, email: ?}.
+func RedactFilter(filter interface{}) string {
+	if filter == nil {
+		return ""
+	}
+
+	raw, err := bson.Marshal(filter)
+
+	if err != nil {
+		return fmt.Sprintf("<%T>", filter)
+	}
+
+	var doc bson.M
+
+	if err = bson.Unmarshal(raw, &doc); err != nil {
+		return fmt.Sprintf("<%T>", filter)
+	}
+
+	return redactValue(doc)
+}
+
+func redactValue(value interface{}) string {
+	switch v := value.(type) {
+	case bson.M:
+		keys := make([]string, 0, len(v))
+
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+
+		for _, key := range keys {
+			parts = append(parts, key+": "+redactValue(v[key]))
+		}
+
+		return "{" + strings.Join(parts, ", ") + "}"
+	case bson.D:
+		parts := make([]string, 0, len(v))
+
+		for _, e := range v {
+			parts = append(parts, e.Key+": "+redactValue(e.Value))
+		}
+
+		return "{" + strings.Join(parts, ", ") + "}"
+	case bson.A:
+		parts := make([]string, 0, len(v))
+
+		for _, item := range v {
+			if _, ok := item.(bson.M); ok {
+				parts = append(parts, redactValue(item))
+			} else if _, ok := item.(bson.D); ok {
+				parts = append(parts, redactValue(item))
+			}
+		}
+
+		if len(parts) == 0 {
+			return "[?]"
+		}
+
+		return "[" + strings.Join(parts, ", ") + "]"
+	default:
+		return "?"
+	}
+}
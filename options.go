@@ -0,0 +1,167 @@
+package remongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The builders below prepend the repository defaults to the caller's
+// options, so anything passed explicitly still takes precedence.
+
+func (mr *MongoRepository[T]) findOneOptions(
+	ctx context.Context,
+	opts []*options.FindOneOptions,
+) []*options.FindOneOptions {
+	defaults := options.FindOne()
+
+	if comment := mr.queryComment(ctx); comment != "" {
+		defaults.SetComment(comment)
+	}
+
+	if hint := mr.queryHint(); hint != nil {
+		defaults.SetHint(hint)
+	}
+
+	return append([]*options.FindOneOptions{defaults}, opts...)
+}
+
+func (mr *MongoRepository[T]) findOptions(
+	ctx context.Context,
+	opts []*options.FindOptions,
+) []*options.FindOptions {
+	defaults := options.Find()
+
+	if comment := mr.queryComment(ctx); comment != "" {
+		defaults.SetComment(comment)
+	}
+
+	if mr.BatchSize > 0 {
+		defaults.SetBatchSize(mr.BatchSize)
+	}
+
+	if mr.NoCursorTimeout {
+		defaults.SetNoCursorTimeout(true)
+	}
+
+	if hint := mr.queryHint(); hint != nil {
+		defaults.SetHint(hint)
+	}
+
+	return append([]*options.FindOptions{defaults}, opts...)
+}
+
+func (mr *MongoRepository[T]) insertOneOptions(
+	ctx context.Context,
+	opts []*options.InsertOneOptions,
+) []*options.InsertOneOptions {
+	defaults := options.InsertOne()
+
+	if comment := mr.queryComment(ctx); comment != "" {
+		defaults.SetComment(comment)
+	}
+
+	return append([]*options.InsertOneOptions{defaults}, opts...)
+}
+
+func (mr *MongoRepository[T]) insertManyOptions(
+	ctx context.Context,
+	opts []*options.InsertManyOptions,
+) []*options.InsertManyOptions {
+	defaults := options.InsertMany()
+
+	if comment := mr.queryComment(ctx); comment != "" {
+		defaults.SetComment(comment)
+	}
+
+	return append([]*options.InsertManyOptions{defaults}, opts...)
+}
+
+func (mr *MongoRepository[T]) replaceOptions(
+	ctx context.Context,
+	opts []*options.ReplaceOptions,
+) []*options.ReplaceOptions {
+	defaults := options.Replace()
+
+	if comment := mr.queryComment(ctx); comment != "" {
+		defaults.SetComment(comment)
+	}
+
+	if hint := mr.queryHint(); hint != nil {
+		defaults.SetHint(hint)
+	}
+
+	return append([]*options.ReplaceOptions{defaults}, opts...)
+}
+
+func (mr *MongoRepository[T]) updateOptions(
+	ctx context.Context,
+	opts []*options.UpdateOptions,
+) []*options.UpdateOptions {
+	defaults := options.Update()
+
+	if comment := mr.queryComment(ctx); comment != "" {
+		defaults.SetComment(comment)
+	}
+
+	if hint := mr.queryHint(); hint != nil {
+		defaults.SetHint(hint)
+	}
+
+	return append([]*options.UpdateOptions{defaults}, opts...)
+}
+
+func (mr *MongoRepository[T]) deleteOptions(
+	ctx context.Context,
+	opts []*options.DeleteOptions,
+) []*options.DeleteOptions {
+	defaults := options.Delete()
+
+	if comment := mr.queryComment(ctx); comment != "" {
+		defaults.SetComment(comment)
+	}
+
+	if hint := mr.queryHint(); hint != nil {
+		defaults.SetHint(hint)
+	}
+
+	return append([]*options.DeleteOptions{defaults}, opts...)
+}
+
+func (mr *MongoRepository[T]) countOptions(
+	ctx context.Context,
+	opts []*options.CountOptions,
+) []*options.CountOptions {
+	defaults := options.Count()
+
+	if comment := mr.queryComment(ctx); comment != "" {
+		defaults.SetComment(comment)
+	}
+
+	if hint := mr.queryHint(); hint != nil {
+		defaults.SetHint(hint)
+	}
+
+	return append([]*options.CountOptions{defaults}, opts...)
+}
+
+func (mr *MongoRepository[T]) aggregateOptions(
+	ctx context.Context,
+	opts []*options.AggregateOptions,
+) []*options.AggregateOptions {
+	defaults := options.Aggregate()
+
+	if comment := mr.queryComment(ctx); comment != "" {
+		defaults.SetComment(comment)
+	}
+
+	if mr.BatchSize > 0 {
+		defaults.SetBatchSize(mr.BatchSize)
+	}
+
+	if hint := mr.queryHint(); hint != nil {
+		defaults.SetHint(hint)
+	}
+
+	return append([]*options.AggregateOptions{defaults}, opts...)
+}
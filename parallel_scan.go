@@ -0,0 +1,172 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type PartitionError struct {
+	Partition int
+	Err       error
+}
+
+func (pe PartitionError) Error() string {
+	return fmt.Sprintf("partition %d: %v", pe.Partition, pe.Err)
+}
+
+func (pe PartitionError) Unwrap() error {
+	return pe.Err
+}
+
+// PartitionErrors collects the failures of a ParallelScan; partitions
+// that failed stop early while the others run to completion.
+type PartitionErrors []PartitionError
+
+func (pe PartitionErrors) Error() string {
+	messages := make([]string, 0, len(pe))
+
+	for _, err := range pe {
+		messages = append(messages, err.Error())
+	}
+
+	return "remongo: parallel scan failed: " + strings.Join(messages, "; ")
+}
+
+func (pe PartitionErrors) Unwrap() []error {
+	errs := make([]error, 0, len(pe))
+
+	for _, err := range pe {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+type idRange struct {
+	min bson.RawValue
+	max bson.RawValue
+}
+
+// ParallelScan splits the collection into _id ranges of roughly equal
+// size and processes each range in its own goroutine.
+func (mr *MongoRepository[T]) ParallelScan(ctx context.Context, partitions int, fn func(T) error) error {
+	done, err := beginOperation()
+
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	ranges, err := mr.idRanges(ctx, partitions)
+
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs PartitionErrors
+	)
+
+	for i, r := range ranges {
+		wg.Add(1)
+
+		go func(partition int, r idRange) {
+			defer wg.Done()
+
+			if err := mr.scanRange(ctx, r, fn); err != nil {
+				mu.Lock()
+				errs = append(errs, PartitionError{Partition: partition, Err: err})
+				mu.Unlock()
+			}
+		}(i, r)
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (mr *MongoRepository[T]) idRanges(ctx context.Context, partitions int) ([]idRange, error) {
+	if partitions < 1 {
+		partitions = 1
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$bucketAuto", Value: bson.M{"groupBy": "$_id", "buckets": partitions}}},
+	}
+
+	cursor, err := mr.collection(ctx).Aggregate(ctx, pipeline, mr.aggregateOptions(ctx, nil)...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets []struct {
+		ID struct {
+			Min bson.RawValue `bson:"min"`
+			Max bson.RawValue `bson:"max"`
+		} `bson:"_id"`
+	}
+
+	if err = cursor.All(ctx, &buckets); err != nil {
+		return nil, err
+	}
+
+	ranges := make([]idRange, 0, len(buckets))
+
+	for i, bucket := range buckets {
+		r := idRange{min: bucket.ID.Min}
+
+		if i < len(buckets)-1 {
+			r.max = buckets[i+1].ID.Min
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	return ranges, nil
+}
+
+func (mr *MongoRepository[T]) scanRange(ctx context.Context, r idRange, fn func(T) error) error {
+	bounds := bson.M{"$gte": r.min}
+
+	if !r.max.IsZero() {
+		bounds["$lt"] = r.max
+	}
+
+	return mr.runContext(ctx, "ParallelScan", bson.M{"_id": bounds}, nil, func(ctx context.Context, op *Operation) error {
+		cursor, err := mr.collection(ctx).Find(ctx, op.Filter, mr.findOptions(ctx, nil)...)
+
+		if err != nil {
+			return err
+		}
+
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			var model T
+
+			if err = mr.decode(ctx, cursor.Current, &model); err != nil {
+				return err
+			}
+
+			if err = fn(model); err != nil {
+				return err
+			}
+		}
+
+		return cursor.Err()
+	})
+}
@@ -0,0 +1,138 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// CollectionResolver picks the collection for a single operation. model
+// is the document being written, or the repository Model for reads.
+type CollectionResolver func(ctx context.Context, model interface{}) string
+
+func (mr *MongoRepository[T]) collection(ctx context.Context) *mongo.Collection {
+	return mr.collectionFor(ctx, mr.Model)
+}
+
+func (mr *MongoRepository[T]) collectionFor(ctx context.Context, model interface{}) *mongo.Collection {
+	name := mr.CollectionName()
+
+	if mr.Resolver != nil {
+		if resolved := mr.Resolver(ctx, model); resolved != "" {
+			name = resolved
+		}
+	}
+
+	opts := mr.collectionOptions()
+
+	if forcePrimary(ctx) {
+		opts.SetReadPreference(readpref.Primary())
+	}
+
+	return mr.Database.Collection(name, opts)
+}
+
+// On returns a copy of the repository bound to collection, e.g. to read
+// archived documents of the same model. The copy ignores the Resolver.
+func (mr *MongoRepository[T]) On(collection string) IMongoRepository[T] {
+	clone := *mr
+	clone.Collection = collection
+	clone.Resolver = nil
+
+	return &clone
+}
+
+func (mr *MongoRepository[T]) WithDatabase(database *mongo.Database) IMongoRepository[T] {
+	clone := *mr
+	clone.Database = database
+
+	return &clone
+}
+
+// MonthlyResolver partitions documents into prefix_YYYY_MM collections by
+// the time stored in field, falling back to the current month for reads
+// and for documents without that field.
+func MonthlyResolver(prefix string, field string) CollectionResolver {
+	return func(ctx context.Context, model interface{}) string {
+		at := time.Now()
+
+		if raw, err := bson.Marshal(model); err == nil {
+			if value, err := bson.Raw(raw).LookupErr(strings.Split(field, ".")...); err == nil {
+				if t, ok := value.TimeOK(); ok && !t.IsZero() {
+					at = t
+				}
+			}
+		}
+
+		return MonthlyPartition(prefix, at)
+	}
+}
+
+func MonthlyPartition(prefix string, at time.Time) string {
+	return fmt.Sprintf("%s_%04d_%02d", prefix, at.UTC().Year(), int(at.UTC().Month()))
+}
+
+// MonthlyPartitions lists the partition names covering from..to inclusive.
+func MonthlyPartitions(prefix string, from time.Time, to time.Time) []string {
+	names := []string{}
+	from = time.Date(from.UTC().Year(), from.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for at := from; !at.After(to.UTC()); at = at.AddDate(0, 1, 0) {
+		names = append(names, MonthlyPartition(prefix, at))
+	}
+
+	return names
+}
+
+// TenantResolver suffixes base with the tenant found in ctx.
+func TenantResolver(base string) CollectionResolver {
+	return func(ctx context.Context, model interface{}) string {
+		if tenant := TenantFromContext(ctx); tenant != "" {
+			return base + "_" + tenant
+		}
+
+		return base
+	}
+}
+
+// FindAcross runs the same query over several partitions and concatenates
+// the results in the order the collections are given.
+func (mr *MongoRepository[T]) FindAcross(
+	ctx context.Context,
+	collections []string,
+	filter interface{},
+	opts ...*options.FindOptions,
+) ([]T, error) {
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	models := []T{}
+
+	for _, name := range collections {
+		cursor, err := mr.Database.Collection(name, mr.collectionOptions()).
+			Find(ctx, query, mr.findOptions(ctx, opts)...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		partition, err := mr.decodeCursor(ctx, cursor)
+
+		if err != nil {
+			return nil, err
+		}
+
+		models = append(models, partition...)
+	}
+
+	return models, nil
+}
@@ -0,0 +1,177 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Projection is a projection document built with Select and Exclude.
+type Projection bson.D
+
+func Select(fields ...string) Projection {
+	return Projection{}.Select(fields...)
+}
+
+func Exclude(fields ...string) Projection {
+	return Projection{}.Exclude(fields...)
+}
+
+func (p Projection) Select(fields ...string) Projection {
+	for _, field := range fields {
+		p = append(p, bson.E{Key: field, Value: 1})
+	}
+
+	return p
+}
+
+func (p Projection) Exclude(fields ...string) Projection {
+	for _, field := range fields {
+		p = append(p, bson.E{Key: field, Value: 0})
+	}
+
+	return p
+}
+
+// FindProjected runs a find with the projection applied and decodes the
+// results into R, which usually declares only the projected fields.
+func FindProjected[R any, T IMongoModel](
+	ctx context.Context,
+	repo IMongoRepository[T],
+	filter interface{},
+	projection Projection,
+	opts ...*options.FindOptions,
+) ([]R, error) {
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(findOptionsFor(ctx, repo, opts), options.Find().SetProjection(bson.D(projection)))
+
+	cursor, err := repo.GetCollection().Find(ctx, query, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := []R{}
+
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func findOptionsFor[T IMongoModel](
+	ctx context.Context,
+	repo IMongoRepository[T],
+	opts []*options.FindOptions,
+) []*options.FindOptions {
+	if mr, ok := repo.(*MongoRepository[T]); ok {
+		return mr.findOptions(ctx, opts)
+	}
+
+	return opts
+}
+
+func toFilter(filter interface{}) (interface{}, error) {
+	if filter == nil {
+		return bson.D{}, nil
+	}
+
+	return ToBson(filter)
+}
+
+// FindAs decodes the matching documents into R instead of the model.
+// Without a projection one is derived from the bson fields of R;
+// otherwise the projection must return every field R declares, so that
+// an incomplete API shape fails fast instead of decoding zero values.
+func FindAs[R any, T IMongoModel](
+	ctx context.Context,
+	repo IMongoRepository[T],
+	filter interface{},
+	projection Projection,
+	opts ...*options.FindOptions,
+) ([]R, error) {
+	var shape R
+	fields := bsonFields(reflect.TypeOf(shape))
+
+	if len(projection) == 0 {
+		for _, field := range fields {
+			projection = projection.Select(field.Name)
+		}
+	} else if missing := uncovered(projection, fields); len(missing) > 0 {
+		return nil, fmt.Errorf("remongo: projection does not return %s of %T", strings.Join(missing, ", "), shape)
+	}
+
+	return FindProjected[R](ctx, repo, filter, projection, opts...)
+}
+
+// uncovered lists the fields a projection leaves out. A field is covered
+// by an inclusion of itself, of a parent or of one of its subfields.
+func uncovered(projection Projection, fields []bsonField) []string {
+	included := []string{}
+	excluded := map[string]bool{}
+
+	for _, e := range projection {
+		if projectionExcludes(e.Value) {
+			excluded[e.Key] = true
+		} else {
+			included = append(included, e.Key)
+		}
+	}
+
+	inclusive := len(included) > 0
+	missing := []string{}
+
+	for _, field := range fields {
+		if excluded[field.Name] {
+			missing = append(missing, field.Name)
+
+			continue
+		}
+
+		if !inclusive || field.Name == "_id" {
+			continue
+		}
+
+		covered := false
+
+		for _, path := range included {
+			if path == field.Name || strings.HasPrefix(path, field.Name+".") || strings.HasPrefix(field.Name, path+".") {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			missing = append(missing, field.Name)
+		}
+	}
+
+	return missing
+}
+
+func projectionExcludes(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return !v
+	case int:
+		return v == 0
+	case int32:
+		return v == 0
+	case int64:
+		return v == 0
+	case float64:
+		return v == 0
+	}
+
+	return false
+}
@@ -0,0 +1,251 @@
+package remongo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const ProjectionCollection = "remongo_projections"
+
+// ProjectionHandler applies one change to the read models. Events may be
+// delivered again after a restart or a rebuild, so handlers must be
+// idempotent.
+type ProjectionHandler func(ctx context.Context, event ChangeEvent) error
+
+type projection struct {
+	source  *mongo.Collection
+	handler ProjectionHandler
+}
+
+// Projector maintains read-model collections from the change streams of
+// one or more source collections. The resume position of every source is
+// stored in ProjectionCollection under the projector name.
+type Projector struct {
+	Database   *mongo.Database
+	Name       string
+	ReadModels []string
+
+	mu          sync.Mutex
+	projections []projection
+}
+
+func InitProjector(database *mongo.Database, name string, readModels ...string) *Projector {
+	return &Projector{
+		Database:   database,
+		Name:       name,
+		ReadModels: readModels,
+	}
+}
+
+func (p *Projector) Register(source ChangeSource, handler ProjectionHandler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.projections = append(p.projections, projection{source: source.GetCollection(), handler: handler})
+}
+
+// Run consumes every registered source until ctx is cancelled or a
+// handler fails, in which case the other sources are stopped as well.
+func (p *Projector) Run(ctx context.Context) error {
+	ctx, done, err := beginWorker(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	p.mu.Lock()
+	projections := append([]projection(nil), p.projections...)
+	p.mu.Unlock()
+
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+	)
+
+	for _, proj := range projections {
+		wg.Add(1)
+
+		go func(proj projection) {
+			defer wg.Done()
+
+			if err := p.consume(ctx, proj); err != nil && ctx.Err() == nil {
+				once.Do(func() {
+					first = err
+					cancel()
+				})
+			}
+		}(proj)
+	}
+
+	wg.Wait()
+
+	return first
+}
+
+// Rebuild drops the read models and replays every source document as an
+// insert event. The change stream position is captured before the replay,
+// so a following Run also applies the changes made while it ran.
+func (p *Projector) Rebuild(ctx context.Context) error {
+	for _, name := range p.ReadModels {
+		if err := p.Database.Collection(name).Drop(ctx); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	projections := append([]projection(nil), p.projections...)
+	p.mu.Unlock()
+
+	for _, proj := range projections {
+		_, err := p.Database.Collection(ProjectionCollection).
+			DeleteOne(ctx, bson.M{"_id": p.tokenID(proj.source)})
+
+		if err != nil {
+			return err
+		}
+
+		if err := p.replay(ctx, proj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Projector) consume(ctx context.Context, proj projection) error {
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+	token, err := p.loadToken(ctx, proj.source)
+
+	if err != nil {
+		return err
+	}
+
+	if token != nil {
+		opts.SetResumeAfter(token)
+	}
+
+	stream, err := proj.source.Watch(ctx, mongo.Pipeline{}, opts)
+
+	if err != nil {
+		return err
+	}
+
+	defer stream.Close(ctx)
+
+	for stream.Next(ctx) {
+		var event ChangeEvent
+
+		if err = stream.Decode(&event); err != nil {
+			return err
+		}
+
+		if err = proj.handler(ctx, event); err != nil {
+			return err
+		}
+
+		if err = p.saveToken(ctx, proj.source, stream.ResumeToken()); err != nil {
+			return err
+		}
+	}
+
+	return stream.Err()
+}
+
+func (p *Projector) replay(ctx context.Context, proj projection) error {
+	stream, err := proj.source.Watch(ctx, mongo.Pipeline{})
+
+	if err != nil {
+		return err
+	}
+
+	token := stream.ResumeToken()
+
+	if err = stream.Close(ctx); err != nil {
+		return err
+	}
+
+	cursor, err := proj.source.Find(ctx, bson.D{})
+
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(ctx)
+
+	namespace := ChangeNamespace{
+		Database:   proj.source.Database().Name(),
+		Collection: proj.source.Name(),
+	}
+
+	for cursor.Next(ctx) {
+		key, err := bson.Marshal(bson.D{{Key: "_id", Value: cursor.Current.Lookup("_id")}})
+
+		if err != nil {
+			return err
+		}
+
+		event := ChangeEvent{
+			OperationType: "insert",
+			Namespace:     namespace,
+			DocumentKey:   key,
+			FullDocument:  append(bson.Raw(nil), cursor.Current...),
+		}
+
+		if err = proj.handler(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	if err = cursor.Err(); err != nil {
+		return err
+	}
+
+	return p.saveToken(ctx, proj.source, token)
+}
+
+func (p *Projector) tokenID(source *mongo.Collection) string {
+	return p.Name + ":" + source.Database().Name() + "." + source.Name()
+}
+
+func (p *Projector) loadToken(ctx context.Context, source *mongo.Collection) (bson.Raw, error) {
+	var state struct {
+		Token bson.Raw `bson:"token"`
+	}
+
+	err := p.Database.Collection(ProjectionCollection).
+		FindOne(ctx, bson.M{"_id": p.tokenID(source)}).
+		Decode(&state)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+
+	return state.Token, err
+}
+
+func (p *Projector) saveToken(ctx context.Context, source *mongo.Collection, token bson.Raw) error {
+	if token == nil {
+		return nil
+	}
+
+	_, err := p.Database.Collection(ProjectionCollection).UpdateOne(
+		ctx,
+		bson.M{"_id": p.tokenID(source)},
+		bson.M{"$set": bson.M{"token": token, "updated_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+
+	return err
+}
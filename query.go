@@ -0,0 +1,219 @@
+package remongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Query is a fluent alternative to the positional Find parameters:
+//
+//	users, err := repo.Query().Where(bson.M{"active": true}).
+//		Sort(remongo.SortBy("created_at", remongo.Desc)).
+//		Limit(20).Skip(40).All(ctx)
+type Query[T IMongoModel] struct {
+	repo       *MongoRepository[T]
+	filters    []interface{}
+	sort       Sort
+	projection Projection
+	limit      int64
+	skip       int64
+}
+
+func (mr *MongoRepository[T]) Query() *Query[T] {
+	return &Query[T]{repo: mr}
+}
+
+// Where adds a filter; several calls are combined with $and.
+func (q *Query[T]) Where(filter interface{}) *Query[T] {
+	q.filters = append(q.filters, filter)
+
+	return q
+}
+
+func (q *Query[T]) Sort(sort Sort) *Query[T] {
+	q.sort = append(q.sort, sort...)
+
+	return q
+}
+
+func (q *Query[T]) Project(projection Projection) *Query[T] {
+	q.projection = append(q.projection, projection...)
+
+	return q
+}
+
+func (q *Query[T]) Limit(limit int64) *Query[T] {
+	q.limit = limit
+
+	return q
+}
+
+func (q *Query[T]) Skip(skip int64) *Query[T] {
+	q.skip = skip
+
+	return q
+}
+
+func (q *Query[T]) Filter() (interface{}, error) {
+	docs := bson.A{}
+
+	for _, filter := range q.filters {
+		doc, err := toFilter(filter)
+
+		if err != nil {
+			return nil, err
+		}
+
+		docs = append(docs, doc)
+	}
+
+	switch len(docs) {
+	case 0:
+		return bson.D{}, nil
+	case 1:
+		return docs[0], nil
+	default:
+		return bson.D{{Key: "$and", Value: docs}}, nil
+	}
+}
+
+func (q *Query[T]) All(ctx context.Context) ([]T, error) {
+	var models []T
+
+	err := q.run(ctx, "All", func(ctx context.Context, filter interface{}) error {
+		cursor, err := q.cursor(ctx, filter, q.limit)
+
+		if err != nil {
+			return err
+		}
+
+		models, err = q.repo.decodeCursor(ctx, cursor)
+
+		return err
+	})
+
+	return models, err
+}
+
+// One returns the first matching document, or nil when nothing matches.
+func (q *Query[T]) One(ctx context.Context) (*T, error) {
+	var model *T
+
+	err := q.run(ctx, "One", func(ctx context.Context, filter interface{}) error {
+		cursor, err := q.cursor(ctx, filter, 1)
+
+		if err != nil {
+			return err
+		}
+
+		defer cursor.Close(ctx)
+
+		if !cursor.Next(ctx) {
+			return cursor.Err()
+		}
+
+		model = new(T)
+
+		return q.repo.decode(ctx, cursor.Current, model)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// Count ignores sort and projection but honors skip and limit.
+func (q *Query[T]) Count(ctx context.Context) (int64, error) {
+	var count int64
+
+	err := q.run(ctx, "Count", func(ctx context.Context, filter interface{}) (err error) {
+		opts := options.Count()
+
+		if q.skip > 0 {
+			opts.SetSkip(q.skip)
+		}
+
+		if q.limit > 0 {
+			opts.SetLimit(q.limit)
+		}
+
+		count, err = q.repo.collection(ctx).
+			CountDocuments(ctx, filter, q.repo.countOptions(ctx, []*options.CountOptions{opts})...)
+
+		return err
+	})
+
+	return count, err
+}
+
+// run executes fn as the Query.op operation of the repository, passing
+// the filter of the query once scopes and middleware applied.
+func (q *Query[T]) run(ctx context.Context, op string, fn func(ctx context.Context, filter interface{}) error) error {
+	filter, err := q.Filter()
+
+	if err != nil {
+		return err
+	}
+
+	return q.repo.runContext(ctx, "Query."+op, filter, nil, func(ctx context.Context, op *Operation) error {
+		return fn(ctx, op.Filter)
+	})
+}
+
+func (q *Query[T]) cursor(ctx context.Context, filter interface{}, limit int64) (*mongo.Cursor, error) {
+	if err := q.sort.Validate(q.repo.Model); err != nil {
+		return nil, err
+	}
+
+	for _, key := range q.sort {
+		if key.NullsLast {
+			return q.aggregate(ctx, filter, limit)
+		}
+	}
+
+	opts := options.Find()
+
+	if len(q.sort) > 0 {
+		opts.SetSort(q.sort.Doc())
+	}
+
+	if len(q.projection) > 0 {
+		opts.SetProjection(bson.D(q.projection))
+	}
+
+	if q.skip > 0 {
+		opts.SetSkip(q.skip)
+	}
+
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+
+	return q.repo.collection(ctx).
+		Find(ctx, filter, q.repo.findOptions(ctx, []*options.FindOptions{opts})...)
+}
+
+func (q *Query[T]) aggregate(ctx context.Context, filter interface{}, limit int64) (*mongo.Cursor, error) {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	pipeline = append(pipeline, q.sort.Stages()...)
+
+	if q.skip > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: q.skip}})
+	}
+
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+
+	if len(q.projection) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: bson.D(q.projection)}})
+	}
+
+	return q.repo.collection(ctx).
+		Aggregate(ctx, pipeline, q.repo.aggregateOptions(ctx, nil)...)
+}
@@ -0,0 +1,98 @@
+package remongo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// slowdownLatency is the ratio of recent to baseline latency above
+	// which a RateLimiter slows down.
+	slowdownLatency = 2
+	minRateFactor   = 1.0 / 16
+)
+
+// RateLimiter paces background jobs to a number of documents per second
+// so that they do not starve production traffic. When the latency it is
+// told about rises well above the baseline it has seen, it lowers the
+// rate, down to a sixteenth, and recovers gradually once latency drops.
+// It is safe for concurrent use and may be shared between jobs.
+type RateLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	factor   float64
+	next     time.Time
+	average  time.Duration
+	baseline time.Duration
+}
+
+func NewRateLimiter(opsPerSecond float64) *RateLimiter {
+	return &RateLimiter{rate: opsPerSecond, factor: 1}
+}
+
+// WithRateLimit paces ForEachBatch to opsPerSecond documents.
+func WithRateLimit(opsPerSecond float64) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.rateLimit = NewRateLimiter(opsPerSecond)
+	}
+}
+
+// Rate is the current documents per second after slowdown.
+func (rl *RateLimiter) Rate() float64 {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	return rl.rate * rl.factor
+}
+
+// Wait accounts for n operations, blocking until the ones accounted for
+// before fit into the rate. A nil RateLimiter never waits.
+func (rl *RateLimiter) Wait(ctx context.Context, n int) error {
+	if rl == nil || rl.rate <= 0 || n <= 0 {
+		return ctx.Err()
+	}
+
+	rl.mu.Lock()
+	now := time.Now()
+
+	if rl.next.Before(now) {
+		rl.next = now
+	}
+
+	delay := rl.next.Sub(now)
+	rl.next = rl.next.Add(time.Duration(float64(n) / (rl.rate * rl.factor) * float64(time.Second)))
+	rl.mu.Unlock()
+
+	return sleepContext(ctx, delay)
+}
+
+// Observe records the latency of one server round trip.
+func (rl *RateLimiter) Observe(latency time.Duration) {
+	if rl == nil {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.average == 0 {
+		rl.average = latency
+	} else {
+		rl.average += (latency - rl.average) / 5
+	}
+
+	// The baseline follows lasting shifts slowly, so that one busy
+	// period does not become the new normal.
+	if rl.baseline == 0 || rl.average < rl.baseline {
+		rl.baseline = rl.average
+	} else {
+		rl.baseline += (rl.average - rl.baseline) / 100
+	}
+
+	if rl.average > slowdownLatency*rl.baseline {
+		rl.factor = max(rl.factor*0.8, minRateFactor)
+	} else {
+		rl.factor = min(rl.factor*1.05, 1)
+	}
+}
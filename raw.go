@@ -0,0 +1,59 @@
+package remongo
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindRaw returns the matching documents undecoded, for hot paths that
+// only read a few fields through DecodeInto.
+func (mr *MongoRepository[T]) FindRaw(
+	ctx context.Context,
+	filter interface{},
+	opts ...*options.FindOptions,
+) ([]bson.Raw, error) {
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	docs := []bson.Raw{}
+
+	err = mr.runContext(ctx, "FindRaw", query, nil, func(ctx context.Context, op *Operation) error {
+		cursor, err := mr.collection(ctx).Find(ctx, op.Filter, mr.findOptions(ctx, opts)...)
+
+		if err != nil {
+			return err
+		}
+
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			docs = append(docs, append(bson.Raw(nil), cursor.Current...))
+		}
+
+		return cursor.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+// DecodeInto decodes the value at a dotted path of raw into v without
+// unmarshalling the rest of the document.
+func DecodeInto(raw bson.Raw, path string, v interface{}) error {
+	value, err := raw.LookupErr(strings.Split(path, ".")...)
+
+	if err != nil {
+		return err
+	}
+
+	return value.Unmarshal(v)
+}
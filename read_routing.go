@@ -0,0 +1,57 @@
+package remongo
+
+import (
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/tag"
+)
+
+// WithReadTags routes reads to the members matching the first tag set
+// that any member satisfies, e.g. {"region": "eu"} followed by an empty
+// set to fall back to any member. A primary read preference, which takes
+// no tags, becomes nearest.
+func WithReadTags(tagSets ...map[string]string) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.readOptions = append(c.readOptions, readpref.WithTagSets(tag.NewTagSetsFromMaps(tagSets)...))
+	}
+}
+
+// WithHedgedReads makes mongos send each read to two members of a shard
+// and use the first reply. Like WithReadTags it needs a non-primary
+// mode.
+func WithHedgedReads(enabled bool) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.readOptions = append(c.readOptions, readpref.WithHedgeEnabled(enabled))
+	}
+}
+
+// routeReads rebuilds base with opts applied over its own options.
+func routeReads(base *readpref.ReadPref, opts []readpref.Option) *readpref.ReadPref {
+	mode := readpref.NearestMode
+	merged := []readpref.Option{}
+
+	if base != nil {
+		if base.Mode() != readpref.PrimaryMode {
+			mode = base.Mode()
+		}
+
+		if tagSets := base.TagSets(); len(tagSets) > 0 {
+			merged = append(merged, readpref.WithTagSets(tagSets...))
+		}
+
+		if staleness, ok := base.MaxStaleness(); ok {
+			merged = append(merged, readpref.WithMaxStaleness(staleness))
+		}
+
+		if hedge := base.HedgeEnabled(); hedge != nil {
+			merged = append(merged, readpref.WithHedgeEnabled(*hedge))
+		}
+	}
+
+	rp, err := readpref.New(mode, append(merged, opts...)...)
+
+	if err != nil {
+		return base
+	}
+
+	return rp
+}
@@ -0,0 +1,45 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+)
+
+// PanicError is returned by Recover for a panic during an operation.
+type PanicError struct {
+	Op    string
+	Value interface{}
+	Stack []byte
+}
+
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("remongo: panic in %s: %v", pe.Op, pe.Value)
+}
+
+// Recover turns panics raised by the inner middleware, hooks, codecs and
+// the driver into *PanicError and logs them with their stack. Register
+// it first so that it wraps everything else. A nil logger logs to
+// log.Default().
+func Recover(logger Logger) Middleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, op *Operation) (err error) {
+			defer func() {
+				if value := recover(); value != nil {
+					pe := &PanicError{Op: op.Name, Value: value, Stack: debug.Stack()}
+
+					logger.Printf("%v\n%s", pe, pe.Stack)
+
+					err = pe
+				}
+			}()
+
+			return next(ctx, op)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package remongo
+
+import (
+	"bytes"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BuildRegistry returns a registry with the driver defaults plus whatever
+// the hooks register, leaving bson.DefaultRegistry untouched.
+func BuildRegistry(hooks ...func(*bsoncodec.Registry)) *bsoncodec.Registry {
+	registry := bson.NewRegistry()
+
+	for _, hook := range hooks {
+		hook(registry)
+	}
+
+	return registry
+}
+
+func (mr *MongoRepository[T]) registry() *bsoncodec.Registry {
+	if mr.Registry != nil {
+		return mr.Registry
+	}
+
+	return bson.DefaultRegistry
+}
+
+func (mr *MongoRepository[T]) collectionOptions() *options.CollectionOptions {
+	opts := mr.WriteOptions.collectionOptions()
+
+	if mr.Registry != nil {
+		opts.SetRegistry(mr.Registry)
+	}
+
+	if mr.ReadPreference != nil {
+		opts.SetReadPreference(mr.ReadPreference)
+	}
+
+	return opts
+}
+
+// unmarshal decodes raw with the repository registry.
+func (mr *MongoRepository[T]) unmarshal(raw bson.Raw, v interface{}) error {
+	dec, err := bson.NewDecoder(bsonrw.NewBSONDocumentReader(raw))
+
+	if err != nil {
+		return err
+	}
+
+	if err = dec.SetRegistry(mr.registry()); err != nil {
+		return err
+	}
+
+	return dec.Decode(v)
+}
+
+// marshal encodes v with the repository registry.
+func (mr *MongoRepository[T]) marshal(v interface{}) (bson.Raw, error) {
+	buf := new(bytes.Buffer)
+	vw, err := bsonrw.NewBSONValueWriter(buf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	enc, err := bson.NewEncoder(vw)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = enc.SetRegistry(mr.registry()); err != nil {
+		return nil, err
+	}
+
+	if err = enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,242 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const PatchCollection = "remongo_patches"
+
+// PatchFunc fixes doc in place and reports whether it changed it. It is
+// called again for documents already fixed when a patch is rerun after a
+// failure, so it must leave them unchanged.
+type PatchFunc func(ctx context.Context, doc bson.M) (bool, error)
+
+// PatchTarget is implemented by MongoRepository for every model type.
+type PatchTarget interface {
+	GetCollection() *mongo.Collection
+}
+
+type Patch struct {
+	Name      string
+	Target    PatchTarget
+	Filter    interface{}
+	Transform PatchFunc
+}
+
+type PatchProgress struct {
+	Patch      string
+	Collection string
+	Scanned    int64
+	Modified   int64
+	Batches    int
+	DryRun     bool
+	Done       bool
+}
+
+// PatchRecord is the ledger entry of an applied patch.
+type PatchRecord struct {
+	Name       string    `bson:"_id"`
+	Collection string    `bson:"collection"`
+	Scanned    int64     `bson:"scanned"`
+	Modified   int64     `bson:"modified"`
+	StartedAt  time.Time `bson:"started_at"`
+	AppliedAt  time.Time `bson:"applied_at"`
+}
+
+// Repair runs registered one-off data fixes in _id order, batch by
+// batch, and records applied ones in PatchCollection so that each runs
+// once. Documents are read and replaced directly, bypassing hooks,
+// validation and caches.
+type Repair struct {
+	Database  *mongo.Database
+	BatchSize int64
+	// BatchDelay is waited between two batches to throttle the load.
+	BatchDelay time.Duration
+	// DryRun counts the documents patches would modify without writing
+	// them or recording the patches.
+	DryRun   bool
+	Progress func(PatchProgress)
+	// RateLimit paces the documents scanned across all patches.
+	RateLimit *RateLimiter
+
+	patches []Patch
+}
+
+func InitRepair(database *mongo.Database, batchSize int64, batchDelay time.Duration) *Repair {
+	return &Repair{
+		Database:   database,
+		BatchSize:  batchSize,
+		BatchDelay: batchDelay,
+	}
+}
+
+func (r *Repair) Register(target PatchTarget, name string, filter interface{}, transform PatchFunc) {
+	r.patches = append(r.patches, Patch{Name: name, Target: target, Filter: filter, Transform: transform})
+}
+
+func (r *Repair) ledger() *mongo.Collection {
+	return r.Database.Collection(PatchCollection)
+}
+
+// Run applies the registered patches not yet in the ledger, in the
+// order they were registered, stopping at the first failure.
+func (r *Repair) Run(ctx context.Context) ([]PatchProgress, error) {
+	applied, err := r.Applied(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	done := map[string]bool{}
+
+	for _, record := range applied {
+		done[record.Name] = true
+	}
+
+	results := []PatchProgress{}
+
+	for _, patch := range r.patches {
+		if done[patch.Name] {
+			continue
+		}
+
+		progress, err := r.apply(ctx, patch)
+		results = append(results, progress)
+
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// Apply runs the named patch even if it was applied before.
+func (r *Repair) Apply(ctx context.Context, name string) (PatchProgress, error) {
+	for _, patch := range r.patches {
+		if patch.Name == name {
+			return r.apply(ctx, patch)
+		}
+	}
+
+	return PatchProgress{}, fmt.Errorf("%w: patch %q", ErrNotFound, name)
+}
+
+// Applied lists the ledger, oldest first.
+func (r *Repair) Applied(ctx context.Context) ([]PatchRecord, error) {
+	cursor, err := r.ledger().Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"applied_at": 1}))
+
+	if err != nil {
+		return nil, err
+	}
+
+	records := []PatchRecord{}
+
+	return records, cursor.All(ctx, &records)
+}
+
+func (r *Repair) apply(ctx context.Context, patch Patch) (PatchProgress, error) {
+	collection := patch.Target.GetCollection()
+	progress := PatchProgress{Patch: patch.Name, Collection: collection.Name(), DryRun: r.DryRun}
+	started := time.Now()
+	batchSize := r.BatchSize
+
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	query, err := toFilter(patch.Filter)
+
+	if err != nil {
+		return progress, err
+	}
+
+	var last interface{}
+
+	for !progress.Done {
+		filter := query
+
+		if last != nil {
+			filter = bson.M{"$and": bson.A{query, bson.M{"_id": bson.M{"$gt": last}}}}
+		}
+
+		fetched := time.Now()
+		cursor, err := collection.Find(ctx, filter, options.Find().SetSort(bson.M{"_id": 1}).SetLimit(batchSize))
+
+		if err != nil {
+			return progress, err
+		}
+
+		var docs []bson.M
+
+		if err = cursor.All(ctx, &docs); err != nil {
+			return progress, err
+		}
+
+		r.RateLimit.Observe(time.Since(fetched))
+
+		if err = r.RateLimit.Wait(ctx, len(docs)); err != nil {
+			return progress, err
+		}
+
+		writes := []mongo.WriteModel{}
+
+		for _, doc := range docs {
+			id := doc["_id"]
+			changed, err := patch.Transform(ctx, doc)
+
+			if err != nil {
+				return progress, fmt.Errorf("remongo: patch %s on %v: %w", patch.Name, id, err)
+			}
+
+			if changed {
+				doc["_id"] = id
+				writes = append(writes, mongo.NewReplaceOneModel().SetFilter(bson.M{"_id": id}).SetReplacement(doc))
+			}
+
+			last = id
+		}
+
+		if len(writes) > 0 && !r.DryRun {
+			if _, err = collection.BulkWrite(ctx, writes); err != nil {
+				return progress, err
+			}
+		}
+
+		progress.Scanned += int64(len(docs))
+		progress.Modified += int64(len(writes))
+		progress.Batches++
+		progress.Done = int64(len(docs)) < batchSize
+
+		if r.Progress != nil {
+			r.Progress(progress)
+		}
+
+		if !progress.Done {
+			if err = sleepContext(ctx, r.BatchDelay); err != nil {
+				return progress, err
+			}
+		}
+	}
+
+	if r.DryRun {
+		return progress, nil
+	}
+
+	_, err = r.ledger().ReplaceOne(ctx, bson.M{"_id": patch.Name}, PatchRecord{
+		Name:       patch.Name,
+		Collection: progress.Collection,
+		Scanned:    progress.Scanned,
+		Modified:   progress.Modified,
+		StartedAt:  started,
+		AppliedAt:  time.Now(),
+	}, options.Replace().SetUpsert(true))
+
+	return progress, err
+}
@@ -2,38 +2,194 @@ package remongo
 
 import (
 	"context"
+	"reflect"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type IMongoModel interface {
 	Collection() string
 }
 
-type IMongoRepository[T IMongoModel] interface {
-	GetDB() *mongo.Database
+// IReadRepository is the subset of the repository that cannot modify
+// data, for reporting services and read models.
+type IReadRepository[T IMongoModel] interface {
 	FindOne(model *T, filter interface{}, opts ...*options.FindOneOptions) error
+	FindOneSorted(model *T, filter interface{}, sort Sort) error
+	FindOneProjected(model *T, filter interface{}, projection Projection) error
+	FindOneWithin(model *T, filter interface{}, maxTime time.Duration) error
 	Find(
-		models []*T,
+		models *[]*T,
 		filter interface{},
 		aggregate interface{},
 		opts ...*options.FindOptions,
 	) error
+	Query() *Query[T]
+	ByID(id interface{}) (bson.M, error)
+	FindByID(ctx context.Context, id interface{}) (*T, error)
+	FindByIDs(ctx context.Context, ids []interface{}) ([]T, error)
+	FindKeyset(
+		ctx context.Context,
+		filter interface{},
+		sortField string,
+		direction SortDirection,
+		limit int64,
+		after string,
+	) (*KeysetPage[T], error)
+	FindLatest(ctx context.Context, filter interface{}, byField string) (*T, error)
+	FindEarliest(ctx context.Context, filter interface{}, byField string) (*T, error)
+	Sample(ctx context.Context, filter interface{}, n int64) ([]T, error)
+	ForEachBatch(ctx context.Context, filter interface{}, batchSize int64, fn func([]T) error) error
+	ParallelScan(ctx context.Context, partitions int, fn func(T) error) error
+	FindRaw(ctx context.Context, filter interface{}, opts ...*options.FindOptions) ([]bson.Raw, error)
+	FindAcross(ctx context.Context, collections []string, filter interface{}, opts ...*options.FindOptions) ([]T, error)
+	Buckets(ctx context.Context, field string, boundaries []interface{}, filter interface{}) ([]Bucket, error)
+	AutoBuckets(ctx context.Context, field string, n int) ([]Bucket, error)
+	GroupByTime(
+		ctx context.Context,
+		field string,
+		granularity Granularity,
+		filter interface{},
+		loc *time.Location,
+	) ([]TimeBucket, error)
+	ValidateSchemaDrift(ctx context.Context) (*SchemaDrift, error)
+	SumMoney(ctx context.Context, field string, filter interface{}) (Money, error)
+	Watch(ctx context.Context, handler ChangeHandler, opts ...*WatchOptions) error
+	Compare(ctx context.Context, other IMongoRepository[T], filter interface{}, keyFields ...string) (*CompareReport, error)
+	FindChildren(ctx context.Context, id interface{}) ([]T, error)
+	FindDescendants(ctx context.Context, id interface{}, maxDepth int) ([]T, error)
+	FindAncestors(ctx context.Context, id interface{}) ([]T, error)
+	Traverse(ctx context.Context, startFilter interface{}, connectFrom string, connectTo string, maxDepth int) (*Traversal[T], error)
+}
+
+type IWriteRepository[T IMongoModel] interface {
 	InsertOne(model *T, opts ...*options.InsertOneOptions) (error, interface{})
 	InsertMany(models *[]T, opts ...*options.InsertManyOptions) (error, interface{})
 	ReplaceOne(filter interface{}, model *T, opts ...*options.ReplaceOptions) (error, int64)
 	UpdateOne(filter interface{}, update interface{}, opts ...*options.UpdateOptions) (error, int64)
 	UpdateMany(filter interface{}, update interface{}, opts ...*options.UpdateOptions) (error, int64)
+	UpdateOneResult(filter interface{}, update interface{}, opts ...*options.UpdateOptions) (error, *UpdateResult)
+	UpdateManyResult(filter interface{}, update interface{}, opts ...*options.UpdateOptions) (error, *UpdateResult)
 	DeleteOne(filter interface{}, opts ...*options.DeleteOptions) (error, int64)
 	DeleteMany(filter interface{}, opts ...*options.DeleteOptions) (error, int64)
+	ArchiveMany(ctx context.Context, filter interface{}, archiveCollection string, archivedAtField string) (int64, error)
+	Anonymize(ctx context.Context, filter interface{}, rules AnonymizeRules) (int64, error)
+	TwoPhaseOp(filter interface{}, apply interface{}, rollback interface{}) (TwoPhaseOp, error)
+	UpdateArrayElement(ctx context.Context, filter interface{}, arrayField string, elemFilter interface{}, update interface{}) (*UpdateResult, error)
+	ReplaceArrayElement(ctx context.Context, filter interface{}, arrayField string, elemFilter interface{}, elem interface{}) (*UpdateResult, error)
+	RemoveArrayElement(ctx context.Context, filter interface{}, arrayField string, elemFilter interface{}) (*UpdateResult, error)
+	InsertManyBatch(ctx context.Context, models []T, opts ...*options.InsertManyOptions) (*BatchResult, error)
+	BulkWrite(ctx context.Context, writes []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*BatchResult, error)
+	BulkUpsertStream(ctx context.Context, in <-chan T, keyFields []string, concurrency int, opts ...*StreamOptions) error
+	UpsertMany(ctx context.Context, models []T, keyFields ...string) ([]UpsertOutcome, error)
+	InsertIdempotent(ctx context.Context, key string, model *T) (*T, bool, error)
+	EnsureIdempotencyIndexes(ctx context.Context, ttl time.Duration) error
+	MergeInto(ctx context.Context, pipeline interface{}, targetCollection string, mergeOpts *MergeOptions) error
+	EnsureTrash(ctx context.Context) error
+	RestoreFromTrash(ctx context.Context, filter interface{}) (int64, error)
+	PurgeTrash(ctx context.Context, filter interface{}) (int64, error)
+	Backfill(ctx context.Context) (int64, error)
+	FinalizeRenames(ctx context.Context) (int64, error)
+	EnsureTreeIndexes(ctx context.Context) error
+	MoveSubtree(ctx context.Context, id interface{}, newParent interface{}) error
+	TransitionTo(ctx context.Context, id interface{}, from string, to string) (*T, error)
+	DecrementIfAtLeast(ctx context.Context, filter interface{}, field string, amount int64) (*T, error)
+	Lease(ctx context.Context, filter interface{}, holder string, ttl time.Duration) (*T, error)
+	ExtendLease(ctx context.Context, id interface{}, holder string, ttl time.Duration) (*T, error)
+	ReleaseLease(ctx context.Context, id interface{}, holder string) error
+}
+
+type IMongoRepository[T IMongoModel] interface {
+	IReadRepository[T]
+	IWriteRepository[T]
+	Commander
+	GetDB() *mongo.Database
+	GetCollection() *mongo.Collection
+	WithDryRun() IMongoRepository[T]
+	WithContext(ctx context.Context) IMongoRepository[T]
+	ForRequest(ctx context.Context) IMongoRepository[T]
+	WithHint(hint interface{}) IMongoRepository[T]
+	WithQueryName(name string) IMongoRepository[T]
+	WithBatchSize(size int32) IMongoRepository[T]
+	WithNoCursorTimeout() IMongoRepository[T]
+	On(collection string) IMongoRepository[T]
+	WithDatabase(database *mongo.Database) IMongoRepository[T]
+	With(opts ...RepositoryOption) IMongoRepository[T]
+	WithCheckpoint(name string) IMongoRepository[T]
+	Scoped(names ...string) IMongoRepository[T]
+	Unfiltered() IMongoRepository[T]
 }
 
 type MongoRepository[T IMongoModel] struct {
 	IMongoRepository[T]
-	Model    T
-	Database *mongo.Database
+	Model        T
+	Database     *mongo.Database
+	Collection   string
+	Resolver     CollectionResolver
+	WriteOptions *WriteOptions
+	Logger       Logger
+	DryRun       bool
+	ServiceName  string
+	TagQueries   bool
+	Hints        map[string]interface{}
+	// BatchSize and NoCursorTimeout are applied to every find cursor.
+	BatchSize       int32
+	NoCursorTimeout bool
+	Registry        *bsoncodec.Registry
+	ReadPreference  *readpref.ReadPref
+	// MaxStaleness and StalePolicy guard secondary reads; see
+	// WithMaxStaleness.
+	MaxStaleness time.Duration
+	StalePolicy  StalePolicy
+	Metrics      MetricsHook
+	IDGenerator  IDGenerator
+	// Cache holds FindOne and Find results for CacheTTL; calls with
+	// explicit options bypass it.
+	Cache         Cache
+	CacheTTL      time.Duration
+	CacheStrategy CacheStrategy
+	// Timeout bounds each CRUD call; Middleware and Hooks wrap them.
+	Timeout    time.Duration
+	Middleware []Middleware
+	Hooks      []Hooks
+	// Trash makes deletes recoverable; see WithTrash.
+	Trash      *TrashOptions
+	ShardKey   bson.D
+	Validator  Validator
+	Migrations map[int]SchemaMigration
+	DecodeMode DecodeMode
+	// Renames are fields moving to a new name; see WithFieldRename.
+	Renames []FieldRename
+	// RateLimit paces ForEachBatch; see WithRateLimit.
+	RateLimit  *RateLimiter
+	Authorizer Authorizer
+	// Scopes name the registered ScopeProviders applied to filters.
+	Scopes []string
+	// NamedScopes are the filters registered with Scope.
+	NamedScopes map[string]interface{}
+	// DefaultFilter is ANDed into reads and updates; see WithDefaultFilter.
+	DefaultFilter bson.D
+	// Transitions guards the state field; see WithTransitions.
+	Transitions *TransitionField
+	// OnDecodeReport receives the partial decodes of DecodeLenient.
+	OnDecodeReport func(ctx context.Context, report DecodeReport)
+	// WriteBackMigrations persists documents upgraded on read.
+	WriteBackMigrations bool
+	// OnScatterGather is called instead of logging when a query lacks
+	// the shard key.
+	OnScatterGather func(op string, collection string, filter interface{})
+
+	ctx         context.Context
+	hint        interface{}
+	queryName   string
+	checkpoint  string
+	scoped      []string
+	writeBehind *writeBehind
 }
 
 func (mr *MongoRepository[T]) GetDB() *mongo.Database {
@@ -41,7 +197,15 @@ func (mr *MongoRepository[T]) GetDB() *mongo.Database {
 }
 
 func (mr *MongoRepository[T]) GetCollection() *mongo.Collection {
-	return mr.Database.Collection(mr.Model.Collection())
+	return mr.collection(mr.context())
+}
+
+func (mr *MongoRepository[T]) CollectionName() string {
+	if mr.Collection != "" {
+		return mr.Collection
+	}
+
+	return mr.Model.Collection()
 }
 
 func (mr *MongoRepository[T]) FindOne(
@@ -49,62 +213,156 @@ func (mr *MongoRepository[T]) FindOne(
 	filter interface{},
 	opts ...*options.FindOneOptions,
 ) error {
-	bson, err := ToBson(filter)
+	return mr.run("FindOne", filter, nil, func(ctx context.Context, op *Operation) error {
+		mr.checkShardFilter(op.Name, op.Filter)
 
-	if err != nil {
-		return err
-	}
+		bson, err := toFilter(op.Filter)
 
-	res := mr.GetCollection().FindOne(context.TODO(), bson, opts...)
+		if err != nil {
+			return err
+		}
 
-	err = res.Decode(&model)
+		key := mr.cacheKey(ctx, op.Name, bson, len(opts))
 
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil
+		if docs, ok := mr.cacheGet(key); ok {
+			if len(docs) == 0 {
+				return nil
+			}
+
+			return mr.decode(ctx, docs[0], model)
 		}
 
-		return err
-	}
+		opts := mr.findOneOptions(ctx, opts)
+
+		res := mr.collection(ctx).FindOne(ctx, bson, opts...)
+
+		raw, err := res.Raw()
+
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				mr.cacheSet(key, false)
+
+				return nil
+			}
 
-	return nil
+			return err
+		}
+
+		_, byID := filterIDs(bson)
+		mr.cacheSet(key, byID, raw)
+
+		return mr.decode(ctx, raw, model)
+	})
 }
 
 func (mr *MongoRepository[T]) Find(
-	models []*T,
+	models *[]*T,
 	filter interface{},
 	aggregate interface{},
 	opts ...*options.FindOptions,
 ) error {
-	bson, err := ToBson(filter)
+	return mr.run("Find", filter, nil, func(ctx context.Context, op *Operation) error {
+		mr.checkShardFilter(op.Name, op.Filter)
 
-	if err != nil {
-		return err
-	}
+		bson, err := toFilter(op.Filter)
 
-	coll := mr.GetCollection()
+		if err != nil {
+			return err
+		}
 
-	if aggregate != nil {
-		coll.Aggregate(context.TODO(), aggregate)
-	}
+		key := ""
 
-	cursor, err := coll.Find(context.TODO(), bson, opts...)
+		var (
+			stages        mongo.Pipeline
+			aggregateOpts *options.AggregateOptions
+		)
 
-	if err = cursor.All(context.TODO(), &models); err != nil {
-		return err
-	}
+		if aggregate == nil {
+			key = mr.cacheKey(ctx, op.Name, bson, len(opts))
+		} else if stages, aggregateOpts, err = findStages(opts); err != nil {
+			return err
+		}
+
+		opts := mr.findOptions(ctx, opts)
+
+		coll := mr.collection(ctx)
+
+		raws, cached := mr.cacheGet(key)
+
+		if !cached {
+			var cursor *mongo.Cursor
 
-	return nil
+			if aggregate != nil {
+				pipeline := append(mongo.Pipeline{{{Key: "$match", Value: bson}}}, toPipeline(aggregate)...)
+				pipeline = append(pipeline, stages...)
+				cursor, err = coll.Aggregate(ctx, pipeline, mr.aggregateOptions(ctx, []*options.AggregateOptions{aggregateOpts})...)
+			} else {
+				cursor, err = coll.Find(ctx, bson, opts...)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err = cursor.All(ctx, &raws); err != nil {
+				return err
+			}
+
+			_, byID := filterIDs(bson)
+			mr.cacheSet(key, byID, raws...)
+		}
+
+		results, err := mr.decodeAll(ctx, raws)
+
+		if err != nil {
+			return err
+		}
+
+		*models = make([]*T, 0, len(results))
+
+		for i := range results {
+			*models = append(*models, &results[i])
+		}
+
+		return nil
+	})
 }
 
 func (mr *MongoRepository[T]) InsertOne(
 	model *T,
 	opts ...*options.InsertOneOptions,
 ) (error, interface{}) {
-	result, err := mr.GetCollection().
-		InsertOne(context.TODO(), model, opts...)
+	var result *mongo.InsertOneResult
 
-	if err != nil {
+	err := mr.run("InsertOne", nil, nil, func(ctx context.Context, op *Operation) error {
+		mr.assignID(model)
+
+		doc, err := mr.prepareDocument(ctx, model)
+
+		if err != nil {
+			return err
+		}
+
+		opts := mr.insertOneOptions(ctx, opts)
+
+		coll := mr.collectionFor(ctx, model)
+
+		if id, deferred, err := mr.deferWrite(op, coll, doc); err != nil || deferred {
+			result = &mongo.InsertOneResult{InsertedID: id}
+
+			return err
+		}
+
+		mr.trackWritten(op, doc)
+
+		return mr.write(ctx, insertIdempotency(doc), func() (err error) {
+			result, err = coll.InsertOne(ctx, doc, opts...)
+
+			return err
+		})
+	})
+
+	if err != nil || result == nil {
 		return err, nil
 	}
 
@@ -115,14 +373,29 @@ func (mr *MongoRepository[T]) InsertMany(
 	models *[]T,
 	opts ...*options.InsertManyOptions,
 ) (error, interface{}) {
-	results, err := mr.GetCollection().
-		InsertMany(context.TODO(), []interface{}{models}, opts...)
+	var result *BatchResult
 
-	if err != nil {
+	err := mr.run("InsertMany", nil, nil, func(ctx context.Context, op *Operation) (err error) {
+		result, err = mr.insertBatch(ctx, *models, opts)
+
+		if err == nil && !result.OK() {
+			err = &BatchError{Result: result}
+		}
+
+		return err
+	})
+
+	if result == nil {
 		return err, nil
 	}
 
-	return nil, results.InsertedIDs
+	ids := make([]interface{}, 0, len(result.Items))
+
+	for _, item := range result.Items {
+		ids = append(ids, item.ID)
+	}
+
+	return err, ids
 }
 
 func (mr *MongoRepository[T]) ReplaceOne(
@@ -130,13 +403,57 @@ func (mr *MongoRepository[T]) ReplaceOne(
 	model *T,
 	opts ...*options.ReplaceOptions,
 ) (error, int64) {
-	result, err := mr.GetCollection().ReplaceOne(context.TODO(), filter, model, opts...)
+	var modified int64
+
+	err := mr.run("ReplaceOne", filter, nil, func(ctx context.Context, op *Operation) error {
+		doc, err := mr.prepareDocument(ctx, model)
+
+		if err != nil {
+			return err
+		}
+
+		mr.checkShardFilter(op.Name, op.Filter)
+
+		opts := mr.replaceOptions(ctx, opts)
+
+		if mr.DryRun {
+			err, modified = mr.dryRun(op.Name, op.Filter, 1)
+
+			return err
+		}
+
+		coll := mr.collection(ctx)
+
+		if replaceUpserts(opts) {
+			if _, deferred, err := mr.deferWrite(op, coll, doc); err != nil || deferred {
+				modified = 1
+
+				return err
+			}
+		}
+
+		mr.trackWritten(op, doc)
+
+		var result *mongo.UpdateResult
+
+		err = mr.write(ctx, filterIdempotency(op.Filter, true), func() (err error) {
+			result, err = coll.ReplaceOne(ctx, op.Filter, doc, opts...)
+
+			return err
+		})
+
+		if err == nil && result != nil {
+			modified = result.ModifiedCount
+		}
+
+		return err
+	})
 
 	if err != nil {
 		return err, 0
 	}
 
-	return nil, result.ModifiedCount
+	return nil, modified
 }
 
 func (mr *MongoRepository[T]) UpdateOne(
@@ -144,8 +461,7 @@ func (mr *MongoRepository[T]) UpdateOne(
 	update interface{},
 	opts ...*options.UpdateOptions,
 ) (error, int64) {
-	result, err := mr.GetCollection().
-		UpdateOne(context.TODO(), filter, update, opts...)
+	err, result := mr.UpdateOneResult(filter, update, opts...)
 
 	if err != nil {
 		return err, 0
@@ -154,51 +470,209 @@ func (mr *MongoRepository[T]) UpdateOne(
 	return nil, result.ModifiedCount
 }
 
+func (mr *MongoRepository[T]) UpdateOneResult(
+	filter interface{},
+	update interface{},
+	opts ...*options.UpdateOptions,
+) (error, *UpdateResult) {
+	var result *mongo.UpdateResult
+
+	err := mr.run("UpdateOne", filter, update, func(ctx context.Context, op *Operation) error {
+		mr.checkShardFilter(op.Name, op.Filter)
+
+		opts := mr.updateOptions(ctx, opts)
+
+		return mr.write(ctx, updateOneIdempotency(op.Filter, op.Update), func() (err error) {
+			result, err = mr.collection(ctx).UpdateOne(ctx, op.Filter, op.Update, opts...)
+
+			return err
+		})
+	})
+
+	if err != nil || result == nil {
+		return err, &UpdateResult{}
+	}
+
+	return nil, newUpdateResult(result)
+}
+
 func (mr *MongoRepository[T]) UpdateMany(
 	filter interface{},
 	update interface{},
 	opts ...*options.UpdateOptions,
 ) (error, int64) {
-	result, err := mr.GetCollection().UpdateMany(context.TODO(), filter, update, opts...)
+	err, result := mr.UpdateManyResult(filter, update, opts...)
 
 	if err != nil {
 		return err, 0
 	}
 
+	if mr.DryRun {
+		return nil, result.MatchedCount
+	}
+
 	return nil, result.ModifiedCount
 }
 
+func (mr *MongoRepository[T]) UpdateManyResult(
+	filter interface{},
+	update interface{},
+	opts ...*options.UpdateOptions,
+) (error, *UpdateResult) {
+	result := &UpdateResult{}
+
+	err := mr.run("UpdateMany", filter, update, func(ctx context.Context, op *Operation) (err error) {
+		mr.checkShardFilter(op.Name, op.Filter)
+
+		opts := mr.updateOptions(ctx, opts)
+
+		if mr.DryRun {
+			err, result.MatchedCount = mr.dryRun(op.Name, op.Filter, 0)
+
+			return err
+		}
+
+		return mr.write(ctx, updateIdempotency(op.Update), func() error {
+			updated, err := mr.collection(ctx).UpdateMany(ctx, op.Filter, op.Update, opts...)
+
+			if err == nil && updated != nil {
+				result = newUpdateResult(updated)
+			}
+
+			return err
+		})
+	})
+
+	if err != nil {
+		return err, &UpdateResult{}
+	}
+
+	return nil, result
+}
+
 func (mr *MongoRepository[T]) DeleteOne(
 	filter interface{},
 	opts ...*options.DeleteOptions,
 ) (error, int64) {
-	result, err := mr.GetCollection().DeleteOne(context.TODO(), filter, opts...)
+	var deleted int64
+
+	err := mr.run("DeleteOne", filter, nil, func(ctx context.Context, op *Operation) error {
+		mr.checkShardFilter(op.Name, op.Filter)
+
+		opts := mr.deleteOptions(ctx, opts)
+
+		if mr.Trash != nil {
+			var err error
+			deleted, err = mr.moveToTrash(ctx, op.Filter, 1, opts)
+
+			return err
+		}
+
+		return mr.write(ctx, filterIdempotency(op.Filter, true), func() error {
+			result, err := mr.collection(ctx).DeleteOne(ctx, op.Filter, opts...)
+
+			if err == nil && result != nil {
+				deleted = result.DeletedCount
+			}
+
+			return err
+		})
+	})
 
 	if err != nil {
 		return err, 0
 	}
 
-	return nil, result.DeletedCount
+	return nil, deleted
 }
 
 func (mr *MongoRepository[T]) DeleteMany(
 	filter interface{},
 	opts ...*options.DeleteOptions,
 ) (error, int64) {
-	result, err := mr.GetCollection().DeleteMany(context.TODO(), filter, opts...)
+	var deleted int64
+
+	err := mr.run("DeleteMany", filter, nil, func(ctx context.Context, op *Operation) (err error) {
+		mr.checkShardFilter(op.Name, op.Filter)
+
+		opts := mr.deleteOptions(ctx, opts)
+
+		if mr.DryRun {
+			err, deleted = mr.dryRun(op.Name, op.Filter, 0)
+
+			return err
+		}
+
+		if mr.Trash != nil {
+			deleted, err = mr.moveToTrash(ctx, op.Filter, 0, opts)
+
+			return err
+		}
+
+		return mr.write(ctx, filterIdempotency(op.Filter, false), func() error {
+			result, err := mr.collection(ctx).DeleteMany(ctx, op.Filter, opts...)
+
+			if err == nil && result != nil {
+				deleted = result.DeletedCount
+			}
+
+			return err
+		})
+	})
 
 	if err != nil {
 		return err, 0
 	}
 
-	return nil, result.DeletedCount
+	return nil, deleted
 }
 
-func InitRepository[T IMongoModel](database *mongo.Database, model IMongoModel) IMongoRepository[T] {
-	return &MongoRepository[T]{
+// Deprecated: InitRepository panics when model is not a T; use
+// NewRepository, which builds the model from the type parameter.
+func InitRepository[T IMongoModel](
+	database *mongo.Database,
+	model IMongoModel,
+	opts ...RepositoryOption,
+) IMongoRepository[T] {
+	repository := &MongoRepository[T]{
 		Database: database,
 		Model:    model.(T),
 	}
+
+	repository.apply(opts)
+
+	if database != nil {
+		registerClient(database.Client())
+	}
+
+	return repository
+}
+
+func NewRepository[T IMongoModel](database *mongo.Database, opts ...RepositoryOption) IMongoRepository[T] {
+	repository := &MongoRepository[T]{
+		Database: database,
+		Model:    newModel[T](),
+	}
+
+	repository.apply(opts)
+
+	if database != nil {
+		registerClient(database.Client())
+	}
+
+	return repository
+}
+
+// newModel returns the zero T, allocating the pointee when T is a
+// pointer so Collection() can be called on it.
+func newModel[T IMongoModel]() T {
+	var model T
+
+	if t := reflect.TypeOf((*T)(nil)).Elem(); t.Kind() == reflect.Ptr {
+		model = reflect.New(t.Elem()).Interface().(T)
+	}
+
+	return model
 }
 
 func ToBson(v interface{}) (doc *bson.D, err error) {
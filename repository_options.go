@@ -0,0 +1,223 @@
+package remongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// RepositoryOption configures a repository built by InitRepository or
+// NewRepository.
+type RepositoryOption func(*repositoryConfig)
+
+type repositoryConfig struct {
+	collection     string
+	logger         Logger
+	timeout        time.Duration
+	middleware     []Middleware
+	readPreference *readpref.ReadPref
+	readOptions    []readpref.Option
+	maxStaleness   time.Duration
+	stalePolicy    StalePolicy
+	metrics        MetricsHook
+	registry       *bsoncodec.Registry
+	hooks          []Hooks
+	tenant         string
+	idGenerator    IDGenerator
+	cache          Cache
+	cacheTTL       time.Duration
+	cacheStrategy  CacheStrategy
+	flushInterval  time.Duration
+	trash          *TrashOptions
+	decodeMode     DecodeMode
+	decodeReport   func(ctx context.Context, report DecodeReport)
+	renames        []FieldRename
+	rateLimit      *RateLimiter
+	authorizer     Authorizer
+	scopes         []string
+	defaultFilter  bson.D
+	transitions    *TransitionField
+}
+
+func WithCollectionName(name string) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.collection = name
+	}
+}
+
+func WithLogger(logger Logger) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.logger = logger
+	}
+}
+
+// WithTimeout bounds every CRUD call of the repository.
+func WithTimeout(timeout time.Duration) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.timeout = timeout
+	}
+}
+
+func WithMiddleware(middleware ...Middleware) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+func WithReadPreference(rp *readpref.ReadPref) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.readPreference = rp
+	}
+}
+
+func WithRegistry(registry *bsoncodec.Registry) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.registry = registry
+	}
+}
+
+func WithHooks(hooks Hooks) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.hooks = append(c.hooks, hooks)
+	}
+}
+
+// WithIDGenerator makes inserts assign ids from generator instead of
+// leaving ObjectIDs to the server.
+func WithIDGenerator(generator IDGenerator) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.idGenerator = generator
+	}
+}
+
+func WithCache(cache Cache, ttl time.Duration) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// WithTenant binds the repository context to tenant, see TenantResolver.
+func WithTenant(tenant string) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.tenant = tenant
+	}
+}
+
+// With returns a shallow copy of the repository with opts applied on top
+// of its current configuration.
+func (mr *MongoRepository[T]) With(opts ...RepositoryOption) IMongoRepository[T] {
+	clone := *mr
+	clone.apply(opts)
+
+	return &clone
+}
+
+func (mr *MongoRepository[T]) apply(opts []RepositoryOption) {
+	config := repositoryConfig{}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.collection != "" {
+		mr.Collection = config.collection
+	}
+
+	if config.logger != nil {
+		mr.Logger = config.logger
+	}
+
+	if config.timeout > 0 {
+		mr.Timeout = config.timeout
+	}
+
+	if config.readPreference != nil {
+		mr.ReadPreference = config.readPreference
+	}
+
+	if config.maxStaleness > 0 {
+		mr.MaxStaleness = config.maxStaleness
+		mr.StalePolicy = config.stalePolicy
+	}
+
+	if config.metrics != nil {
+		mr.Metrics = config.metrics
+	}
+
+	if len(config.readOptions) > 0 {
+		mr.ReadPreference = routeReads(mr.ReadPreference, config.readOptions)
+	}
+
+	if config.registry != nil {
+		mr.Registry = config.registry
+	}
+
+	if config.idGenerator != nil {
+		mr.IDGenerator = config.idGenerator
+	}
+
+	if config.cache != nil {
+		mr.Cache = config.cache
+		mr.CacheTTL = config.cacheTTL
+	}
+
+	if config.cacheStrategy != CacheInvalidate {
+		mr.CacheStrategy = config.cacheStrategy
+	}
+
+	if config.cacheStrategy == CacheWriteBehind && mr.writeBehind == nil {
+		interval := config.flushInterval
+
+		if interval <= 0 {
+			interval = time.Second
+		}
+
+		mr.writeBehind = newWriteBehind(interval, mr.flushWrites)
+		registerCloser(mr.writeBehind.Close)
+	}
+
+	if config.decodeMode != DecodeDefault {
+		mr.DecodeMode = config.decodeMode
+		mr.OnDecodeReport = config.decodeReport
+	}
+
+	if len(config.renames) > 0 {
+		mr.Renames = append(append([]FieldRename{}, mr.Renames...), config.renames...)
+	}
+
+	if config.rateLimit != nil {
+		mr.RateLimit = config.rateLimit
+	}
+
+	if config.authorizer != nil {
+		mr.Authorizer = config.authorizer
+	}
+
+	if config.scopes != nil {
+		mr.Scopes = config.scopes
+	}
+
+	if config.defaultFilter != nil {
+		mr.DefaultFilter = config.defaultFilter
+	}
+
+	if config.transitions != nil {
+		mr.Transitions = config.transitions
+	}
+
+	if config.trash != nil {
+		mr.Trash = config.trash
+	}
+
+	if config.tenant != "" {
+		mr.ctx = ContextWithTenant(mr.context(), config.tenant)
+	}
+
+	// Full slice expressions keep clones from sharing appended elements.
+	mr.Middleware = append(mr.Middleware[:len(mr.Middleware):len(mr.Middleware)], config.middleware...)
+	mr.Hooks = append(mr.Hooks[:len(mr.Hooks):len(mr.Hooks)], config.hooks...)
+}
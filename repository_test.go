@@ -0,0 +1,95 @@
+package remongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestFindFillsModels(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("find", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.orders", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: 1}, {Key: "status", Value: "paid"}},
+			bson.D{{Key: "_id", Value: 2}, {Key: "status", Value: "shipped"}},
+		))
+
+		repo := NewRepository[transitionOrder](mt.DB)
+		models := []*transitionOrder{}
+
+		if err := repo.Find(&models, bson.M{}, nil, options.Find().SetBatchSize(5)); err != nil {
+			mt.Fatal(err)
+		}
+
+		if len(models) != 2 || models[0].Status != "paid" || models[1].ID != 2 {
+			mt.Fatalf("want both orders, got %v", models)
+		}
+
+		if size := mt.GetStartedEvent().Command.Lookup("batchSize").Int32(); size != 5 {
+			mt.Fatalf("want batch size 5, got %d", size)
+		}
+	})
+
+	mt.Run("find with aggregate", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.orders", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: 2}, {Key: "status", Value: "shipped"}},
+		))
+
+		repo := NewRepository[transitionOrder](mt.DB)
+		models := []*transitionOrder{}
+		aggregate := mongo.Pipeline{{{Key: "$addFields", Value: bson.M{"seen": true}}}}
+
+		err := repo.Find(&models, bson.M{}, aggregate, options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(1))
+
+		if err != nil {
+			mt.Fatal(err)
+		}
+
+		if len(models) != 1 || models[0].ID != 2 {
+			mt.Fatalf("want the last order, got %v", models)
+		}
+
+		stages, _ := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Values()
+		names := []string{}
+
+		for _, stage := range stages {
+			elements, _ := stage.Document().Elements()
+			names = append(names, elements[0].Key())
+		}
+
+		if len(names) != 4 || names[2] != "$sort" || names[3] != "$limit" {
+			mt.Fatalf("want $match, $addFields, $sort, $limit, got %v", names)
+		}
+	})
+}
+
+func TestInsertManySendsEveryModel(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("insert many", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}))
+
+		repo := NewRepository[transitionOrder](mt.DB)
+		models := []transitionOrder{{ID: 1, Status: "pending"}, {ID: 2, Status: "pending"}}
+
+		if err, _ := repo.InsertMany(&models); err != nil {
+			mt.Fatal(err)
+		}
+
+		docs, _ := mt.GetStartedEvent().Command.Lookup("documents").Array().Values()
+
+		if len(docs) != 2 {
+			mt.Fatalf("want 2 documents, got %d", len(docs))
+		}
+
+		for i, doc := range docs {
+			if id := doc.Document().Lookup("_id").Int32(); id != int32(i+1) {
+				mt.Fatalf("want _id %d, got %v", i+1, doc)
+			}
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package remongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RetentionAction string
+
+const (
+	RetentionDelete  RetentionAction = "delete"
+	RetentionArchive RetentionAction = "archive"
+)
+
+// RetentionRule removes or archives documents whose Field is older than
+// OlderThan and which also match Filter.
+type RetentionRule struct {
+	Name              string
+	Field             string
+	OlderThan         time.Duration
+	Filter            interface{}
+	Action            RetentionAction
+	ArchiveCollection string
+	ArchivedAtField   string
+}
+
+type RetentionProgress struct {
+	Rule       string
+	Collection string
+	Processed  int64
+	Batches    int
+	Done       bool
+}
+
+// RetentionTarget is implemented by MongoRepository for every model type.
+type RetentionTarget interface {
+	GetCollection() *mongo.Collection
+	RetentionBatch(ctx context.Context, rule RetentionRule, cutoff time.Time, batchSize int64) (int64, error)
+}
+
+// RetentionModel lets a model declare its own rules, used by Register
+// when no explicit rules are given.
+type RetentionModel interface {
+	RetentionRules() []RetentionRule
+}
+
+type retentionEntry struct {
+	target RetentionTarget
+	rules  []RetentionRule
+}
+
+type RetentionRunner struct {
+	BatchSize int64
+	// BatchDelay is waited between two batches to limit the load put on
+	// the cluster by background cleanup.
+	BatchDelay time.Duration
+	Progress   func(RetentionProgress)
+	// RateLimit paces the documents processed across all rules.
+	RateLimit *RateLimiter
+
+	entries []retentionEntry
+}
+
+func InitRetentionRunner(batchSize int64, batchDelay time.Duration) *RetentionRunner {
+	return &RetentionRunner{
+		BatchSize:  batchSize,
+		BatchDelay: batchDelay,
+	}
+}
+
+func (rr *RetentionRunner) Register(target RetentionTarget, rules ...RetentionRule) {
+	if declared, ok := target.(RetentionModel); ok && len(rules) == 0 {
+		rules = declared.RetentionRules()
+	}
+
+	rr.entries = append(rr.entries, retentionEntry{target: target, rules: rules})
+}
+
+// Run applies every registered rule once, batch by batch, until no
+// document is left to process or ctx is cancelled.
+func (rr *RetentionRunner) Run(ctx context.Context) error {
+	for _, entry := range rr.entries {
+		for _, rule := range entry.rules {
+			if err := rr.runRule(ctx, entry.target, rule); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (rr *RetentionRunner) runRule(ctx context.Context, target RetentionTarget, rule RetentionRule) error {
+	progress := RetentionProgress{Rule: rule.Name, Collection: target.GetCollection().Name()}
+	cutoff := time.Now().Add(-rule.OlderThan)
+
+	for {
+		started := time.Now()
+		processed, err := target.RetentionBatch(ctx, rule, cutoff, rr.BatchSize)
+
+		if err != nil {
+			return err
+		}
+
+		rr.RateLimit.Observe(time.Since(started))
+
+		if err = rr.RateLimit.Wait(ctx, int(processed)); err != nil {
+			return err
+		}
+
+		progress.Processed += processed
+		progress.Batches++
+		progress.Done = processed < rr.BatchSize || processed == 0 || dryRunning(target)
+
+		if rr.Progress != nil {
+			rr.Progress(progress)
+		}
+
+		if progress.Done {
+			return nil
+		}
+
+		if err = sleepContext(ctx, rr.BatchDelay); err != nil {
+			return err
+		}
+	}
+}
+
+// dryRunning reports whether target only counts its batches, which then
+// never shrink.
+func dryRunning(target RetentionTarget) bool {
+	dry, ok := target.(interface{ dryRunning() bool })
+
+	return ok && dry.dryRunning()
+}
+
+func (mr *MongoRepository[T]) dryRunning() bool {
+	return mr.DryRun
+}
+
+func (mr *MongoRepository[T]) RetentionRules() []RetentionRule {
+	if declared, ok := any(mr.Model).(RetentionModel); ok {
+		return declared.RetentionRules()
+	}
+
+	return nil
+}
+
+// RetentionBatch processes at most batchSize documents of rule that are
+// older than cutoff and returns how many were removed. Deletes go through
+// DeleteMany, so they honor the trash, dry run, cache and hooks of the
+// repository; a dry run processes only the first batch of a rule.
+func (mr *MongoRepository[T]) RetentionBatch(
+	ctx context.Context,
+	rule RetentionRule,
+	cutoff time.Time,
+	batchSize int64,
+) (int64, error) {
+	filter := bson.A{bson.M{rule.Field: bson.M{"$lt": cutoff}}}
+
+	if rule.Filter != nil {
+		query, err := ToBson(rule.Filter)
+
+		if err != nil {
+			return 0, err
+		}
+
+		filter = append(filter, query)
+	}
+
+	var docs []bson.M
+
+	err := mr.runContext(ctx, "RetentionBatch", bson.M{"$and": filter}, nil, func(ctx context.Context, op *Operation) error {
+		cursor, err := mr.collection(ctx).Find(
+			ctx,
+			op.Filter,
+			options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(batchSize),
+		)
+
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &docs)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(docs) == 0 {
+		return 0, nil
+	}
+
+	ids := make(bson.A, 0, len(docs))
+
+	for _, doc := range docs {
+		ids = append(ids, doc["_id"])
+	}
+
+	batch := bson.M{"_id": bson.M{"$in": ids}}
+
+	if rule.Action == RetentionArchive {
+		if mr.DryRun {
+			err, archived := mr.dryRun("ArchiveMany", batch, 0)
+
+			return archived, err
+		}
+
+		return mr.ArchiveMany(ctx, batch, rule.ArchiveCollection, rule.ArchivedAtField)
+	}
+
+	err, deleted := mr.WithContext(ctx).DeleteMany(batch)
+
+	return deleted, err
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
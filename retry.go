@@ -0,0 +1,203 @@
+package remongo
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Idempotency says whether a write can be sent again after a failover
+// without being applied twice.
+type Idempotency int
+
+const (
+	// Unsafe writes, such as $inc or inserts without an _id, are never
+	// retried.
+	Unsafe Idempotency = iota
+	Idempotent
+	// IdempotentInsert is an insert with a client-generated _id. A
+	// duplicate key on _id after a retry means the first attempt landed.
+	IdempotentInsert
+)
+
+const defaultRetryBackoff = 100 * time.Millisecond
+
+// Server codes returned while a replica set has no writable primary.
+var failoverCodes = []int{
+	91,    // ShutdownInProgress
+	189,   // PrimarySteppedDown
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// nonIdempotentOperators change a document relative to its current
+// state, so applying them twice differs from applying them once.
+var nonIdempotentOperators = map[string]bool{
+	"$inc":  true,
+	"$mul":  true,
+	"$push": true,
+	"$pop":  true,
+	"$bit":  true,
+}
+
+func isFailover(err error) bool {
+	if mongo.IsNetworkError(err) {
+		return true
+	}
+
+	var se mongo.ServerError
+
+	if !errors.As(err, &se) {
+		return false
+	}
+
+	if se.HasErrorLabel("RetryableWriteError") {
+		return true
+	}
+
+	for _, code := range failoverCodes {
+		if se.HasErrorCode(code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// insertIdempotency classifies inserts of encoded documents: without an
+// _id the driver generates a new one on every attempt.
+func insertIdempotency(docs ...interface{}) Idempotency {
+	for _, doc := range docs {
+		raw, ok := doc.(bson.Raw)
+
+		if !ok {
+			return Unsafe
+		}
+
+		if _, err := raw.LookupErr("_id"); err != nil {
+			return Unsafe
+		}
+	}
+
+	return IdempotentInsert
+}
+
+func updateIdempotency(update interface{}) Idempotency {
+	raw, err := bson.Marshal(update)
+
+	if err != nil {
+		// Pipelines and other arrays are not inspected.
+		return Unsafe
+	}
+
+	elements, err := bson.Raw(raw).Elements()
+
+	if err != nil {
+		return Unsafe
+	}
+
+	for _, element := range elements {
+		if nonIdempotentOperators[element.Key()] {
+			return Unsafe
+		}
+	}
+
+	return Idempotent
+}
+
+// filterIdempotency classifies writes by their filter. A retry may match
+// other documents than the first attempt changed, so a write to a single
+// document is only safe when filter pins one _id, and a write to several
+// when it pins their _ids.
+func filterIdempotency(filter interface{}, single bool) Idempotency {
+	if ids, ok := filterIDs(filter); ok && (!single || len(ids) == 1) {
+		return Idempotent
+	}
+
+	// Scopes AND their fragments into the filter of the caller.
+	raw, err := bson.Marshal(filter)
+
+	if err != nil {
+		return Unsafe
+	}
+
+	clauses, ok := bson.Raw(raw).Lookup("$and").ArrayOK()
+
+	if !ok {
+		return Unsafe
+	}
+
+	values, _ := clauses.Values()
+
+	for _, clause := range values {
+		if doc, ok := clause.DocumentOK(); ok && filterIdempotency(doc, single) == Idempotent {
+			return Idempotent
+		}
+	}
+
+	return Unsafe
+}
+
+// updateOneIdempotency requires both a safe update and a pinned _id.
+func updateOneIdempotency(filter interface{}, update interface{}) Idempotency {
+	if updateIdempotency(update) == Unsafe {
+		return Unsafe
+	}
+
+	return filterIdempotency(filter, true)
+}
+
+func bulkIdempotency(writes []mongo.WriteModel) Idempotency {
+	for _, write := range writes {
+		safe := Idempotent
+
+		switch w := write.(type) {
+		case *mongo.InsertOneModel:
+			doc, err := bson.Marshal(w.Document)
+
+			if err != nil {
+				return Unsafe
+			}
+
+			safe = insertIdempotency(bson.Raw(doc))
+		case *mongo.UpdateOneModel:
+			safe = updateOneIdempotency(w.Filter, w.Update)
+		case *mongo.UpdateManyModel:
+			safe = updateIdempotency(w.Update)
+		case *mongo.ReplaceOneModel:
+			safe = filterIdempotency(w.Filter, true)
+		case *mongo.DeleteOneModel:
+			safe = filterIdempotency(w.Filter, true)
+		case *mongo.DeleteManyModel:
+			safe = filterIdempotency(w.Filter, false)
+		}
+
+		if safe == Unsafe {
+			return Unsafe
+		}
+	}
+
+	// Duplicate keys in a bulk write are reported per document, so even
+	// inserts are not treated as landed.
+	return Idempotent
+}
+
+func isDuplicateID(err error) bool {
+	var dup *DuplicateKeyError
+
+	if !errors.As(translateError(err), &dup) {
+		return false
+	}
+
+	if len(dup.Fields) > 0 {
+		return len(dup.Fields) == 1 && dup.Fields[0] == "_id"
+	}
+
+	return strings.Contains(dup.Index, "_id_")
+}
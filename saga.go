@@ -0,0 +1,217 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const SagaCollection = "remongo_sagas"
+
+type SagaStatus string
+
+const (
+	SagaRunning      SagaStatus = "running"
+	SagaCompleted    SagaStatus = "completed"
+	SagaCompensating SagaStatus = "compensating"
+	SagaCompensated  SagaStatus = "compensated"
+)
+
+// SagaFunc performs or undoes one step. Data is shared by the steps of a
+// run and persisted after each of them, so actions can record what a
+// later compensation needs, such as inserted IDs.
+type SagaFunc func(ctx context.Context, data bson.M) error
+
+type SagaStep struct {
+	Name       string
+	Action     SagaFunc
+	Compensate SagaFunc
+}
+
+// SagaState is the persisted progress of one run. Step is the number of
+// steps whose action has completed and not yet been compensated.
+type SagaState struct {
+	ID        string     `bson:"_id"`
+	Saga      string     `bson:"saga"`
+	Status    SagaStatus `bson:"status"`
+	Step      int        `bson:"step"`
+	Data      bson.M     `bson:"data"`
+	Error     string     `bson:"error,omitempty"`
+	UpdatedAt time.Time  `bson:"updated_at"`
+}
+
+// Saga runs steps in order and, when one fails, compensates the
+// completed ones in reverse. Progress is stored in SagaCollection, so a
+// run interrupted by a crash continues with Resume. A step may run again
+// if the crash happened before its progress was saved, so actions and
+// compensations must be idempotent.
+type Saga struct {
+	Database *mongo.Database
+	Name     string
+	Steps    []SagaStep
+}
+
+func InitSaga(database *mongo.Database, name string) *Saga {
+	return &Saga{Database: database, Name: name}
+}
+
+// Step appends a step and returns the saga for chaining.
+func (s *Saga) Step(name string, action SagaFunc, compensate SagaFunc) *Saga {
+	s.Steps = append(s.Steps, SagaStep{Name: name, Action: action, Compensate: compensate})
+
+	return s
+}
+
+func (s *Saga) states() *mongo.Collection {
+	return s.Database.Collection(SagaCollection)
+}
+
+// Run starts the run id with data. A run that already exists is resumed
+// instead. The returned error is the failure that triggered compensation.
+func (s *Saga) Run(ctx context.Context, id string, data bson.M) (*SagaState, error) {
+	if data == nil {
+		data = bson.M{}
+	}
+
+	state := &SagaState{ID: id, Saga: s.Name, Status: SagaRunning, Data: data, UpdatedAt: time.Now()}
+
+	_, err := s.states().InsertOne(ctx, state)
+
+	if mongo.IsDuplicateKeyError(err) {
+		return s.Resume(ctx, id)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return state, s.advance(ctx, state)
+}
+
+// Resume continues the run id from its persisted state.
+func (s *Saga) Resume(ctx context.Context, id string) (*SagaState, error) {
+	state, err := s.State(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return state, s.advance(ctx, state)
+}
+
+// Compensate undoes the completed steps of the run id, e.g. to cancel a
+// workflow that is still running.
+func (s *Saga) Compensate(ctx context.Context, id string) (*SagaState, error) {
+	state, err := s.State(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Status == SagaRunning || state.Status == SagaCompleted {
+		state.Status = SagaCompensating
+
+		if err = s.save(ctx, state); err != nil {
+			return state, err
+		}
+	}
+
+	return state, s.advance(ctx, state)
+}
+
+func (s *Saga) State(ctx context.Context, id string) (*SagaState, error) {
+	state := &SagaState{}
+	err := s.states().FindOne(ctx, bson.M{"_id": id, "saga": s.Name}).Decode(state)
+
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	if state.Data == nil {
+		state.Data = bson.M{}
+	}
+
+	return state, nil
+}
+
+// Pending lists the runs left running or compensating, which is what a
+// process resumes after a restart.
+func (s *Saga) Pending(ctx context.Context) ([]SagaState, error) {
+	cursor, err := s.states().Find(ctx, bson.M{
+		"saga":   s.Name,
+		"status": bson.M{"$in": bson.A{SagaRunning, SagaCompensating}},
+	}, options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}}))
+
+	if err != nil {
+		return nil, err
+	}
+
+	states := []SagaState{}
+
+	if err = cursor.All(ctx, &states); err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
+
+func (s *Saga) advance(ctx context.Context, state *SagaState) error {
+	var failure error
+
+	for state.Status == SagaRunning && state.Step < len(s.Steps) {
+		if failure = s.Steps[state.Step].Action(ctx, state.Data); failure != nil {
+			state.Status = SagaCompensating
+			state.Error = s.Steps[state.Step].Name + ": " + failure.Error()
+		} else {
+			state.Step++
+		}
+
+		if err := s.save(ctx, state); err != nil {
+			return errors.Join(failure, err)
+		}
+	}
+
+	if state.Status == SagaRunning {
+		state.Status = SagaCompleted
+
+		return s.save(ctx, state)
+	}
+
+	for state.Status == SagaCompensating && state.Step > 0 {
+		step := s.Steps[state.Step-1]
+
+		if step.Compensate != nil {
+			if err := step.Compensate(ctx, state.Data); err != nil {
+				return errors.Join(failure, err)
+			}
+		}
+
+		state.Step--
+
+		if err := s.save(ctx, state); err != nil {
+			return errors.Join(failure, err)
+		}
+	}
+
+	if state.Status == SagaCompensating {
+		state.Status = SagaCompensated
+
+		if err := s.save(ctx, state); err != nil {
+			return errors.Join(failure, err)
+		}
+	}
+
+	return failure
+}
+
+func (s *Saga) save(ctx context.Context, state *SagaState) error {
+	state.UpdatedAt = time.Now()
+
+	_, err := s.states().ReplaceOne(ctx, bson.M{"_id": state.ID}, state)
+
+	return err
+}
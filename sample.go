@@ -0,0 +1,39 @@
+package remongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Sample returns up to n randomly chosen documents matching filter.
+func (mr *MongoRepository[T]) Sample(ctx context.Context, filter interface{}, n int64) ([]T, error) {
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var models []T
+
+	err = mr.runContext(ctx, "Sample", query, nil, func(ctx context.Context, op *Operation) error {
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: op.Filter}},
+			{{Key: "$sample", Value: bson.M{"size": n}}},
+		}
+
+		cursor, err := mr.collection(ctx).
+			Aggregate(ctx, pipeline, mr.aggregateOptions(ctx, nil)...)
+
+		if err != nil {
+			return err
+		}
+
+		models, err = mr.decodeCursor(ctx, cursor)
+
+		return err
+	})
+
+	return models, err
+}
@@ -0,0 +1,234 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const ScheduleCollection = "remongo_schedules"
+
+// MissedRunPolicy decides what happens to activations that passed while
+// no runner was leading (downtime, failover, long callbacks).
+type MissedRunPolicy string
+
+const (
+	// MissedRunSkip drops missed activations and waits for the next one.
+	MissedRunSkip MissedRunPolicy = "skip"
+	// MissedRunOnce runs a single catch-up activation for all missed ones.
+	MissedRunOnce MissedRunPolicy = "once"
+	// MissedRunAll runs every missed activation in order, up to MaxCatchUp.
+	MissedRunAll MissedRunPolicy = "all"
+)
+
+type ScheduledFunc func(ctx context.Context, scheduledAt time.Time) error
+
+type ScheduleRecord struct {
+	Name      string          `bson:"_id"`
+	Spec      string          `bson:"spec"`
+	Policy    MissedRunPolicy `bson:"policy"`
+	NextRun   time.Time       `bson:"next_run"`
+	LastRun   *time.Time      `bson:"last_run,omitempty"`
+	LastError string          `bson:"last_error,omitempty"`
+}
+
+type scheduledTask struct {
+	schedule *CronSchedule
+	policy   MissedRunPolicy
+	callback ScheduledFunc
+}
+
+type Scheduler struct {
+	Database *mongo.Database
+	Leader   *LeaderElector
+	// Interval between checks for due schedules.
+	Interval time.Duration
+	// MaxCatchUp bounds the activations replayed by MissedRunAll.
+	MaxCatchUp int
+
+	mu    sync.Mutex
+	tasks map[string]*scheduledTask
+}
+
+func InitScheduler(database *mongo.Database, leader *LeaderElector) *Scheduler {
+	return &Scheduler{
+		Database:   database,
+		Leader:     leader,
+		Interval:   time.Second * 10,
+		MaxCatchUp: 100,
+		tasks:      map[string]*scheduledTask{},
+	}
+}
+
+func (s *Scheduler) GetCollection() *mongo.Collection {
+	return s.Database.Collection(ScheduleCollection)
+}
+
+// Register stores the schedule and attaches the callback that is fired
+// when this instance is the elected runner.
+func (s *Scheduler) Register(
+	ctx context.Context,
+	name string,
+	spec string,
+	policy MissedRunPolicy,
+	callback ScheduledFunc,
+) error {
+	schedule, err := ParseCron(spec)
+
+	if err != nil {
+		return err
+	}
+
+	record := ScheduleRecord{}
+	err = s.GetCollection().FindOne(ctx, bson.M{"_id": name}).Decode(&record)
+
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	set := bson.M{"spec": spec, "policy": policy}
+
+	if err != nil || record.Spec != spec {
+		set["next_run"] = schedule.Next(time.Now())
+	}
+
+	_, err = s.GetCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": name},
+		bson.M{"$set": set},
+		options.Update().SetUpsert(true),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.tasks[name] = &scheduledTask{schedule: schedule, policy: policy, callback: callback}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Start runs the scheduling loop until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) error {
+	ctx, done, err := beginWorker(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			if s.Leader != nil {
+				s.Leader.Release(context.Background())
+			}
+
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick runs every due schedule once if this instance holds the lead.
+func (s *Scheduler) Tick(ctx context.Context) error {
+	if s.Leader != nil {
+		leader, err := s.Leader.TryAcquire(ctx)
+
+		if err != nil || !leader {
+			return err
+		}
+	}
+
+	now := time.Now()
+	cursor, err := s.GetCollection().
+		Find(ctx, bson.M{"next_run": bson.M{"$lte": now}})
+
+	if err != nil {
+		return err
+	}
+
+	var records []ScheduleRecord
+
+	if err = cursor.All(ctx, &records); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		s.mu.Lock()
+		task, ok := s.tasks[record.Name]
+		s.mu.Unlock()
+
+		if !ok {
+			continue
+		}
+
+		if err = s.run(ctx, record, task, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Scheduler) run(ctx context.Context, record ScheduleRecord, task *scheduledTask, now time.Time) error {
+	var due []time.Time
+
+	for at := record.NextRun; !at.IsZero() && !at.After(now); at = task.schedule.Next(at) {
+		due = append(due, at)
+
+		if task.policy == MissedRunAll && len(due) >= s.MaxCatchUp {
+			break
+		}
+
+		if task.policy != MissedRunAll && task.policy != MissedRunOnce && len(due) > 1 {
+			break
+		}
+	}
+
+	switch task.policy {
+	case MissedRunOnce:
+		if len(due) > 1 {
+			due = due[len(due)-1:]
+		}
+	case MissedRunAll:
+	default:
+		if len(due) > 1 {
+			due = nil
+		}
+	}
+
+	set := bson.M{"next_run": task.schedule.Next(now), "last_error": ""}
+
+	for _, at := range due {
+		set["last_run"] = at
+
+		if err := task.callback(ctx, at); err != nil {
+			set["last_error"] = err.Error()
+			break
+		}
+	}
+
+	_, err := s.GetCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": record.Name, "next_run": record.NextRun},
+		bson.M{"$set": set},
+	)
+
+	return err
+}
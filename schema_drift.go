@@ -0,0 +1,305 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrSchemaDrift = errors.New("remongo: schema drift")
+
+// IndexedModel declares the indexes a collection is expected to have.
+type IndexedModel interface {
+	Indexes() []mongo.IndexModel
+}
+
+// SchemaModel declares the $jsonSchema validator of its collection.
+type SchemaModel interface {
+	JSONSchema() bson.M
+}
+
+// IndexDrift is an index whose keys match the declaration but whose
+// options do not. Options holds the differing option names.
+type IndexDrift struct {
+	Keys    string
+	Options []string
+}
+
+// SchemaDrift is the difference between the declarations of the model
+// and the collection. Indexes are identified by their key pattern, e.g.
+// "email_1" or "tenant_1_created_at_-1".
+type SchemaDrift struct {
+	Collection        string
+	MissingIndexes    []string
+	ExtraIndexes      []string
+	ChangedIndexes    []IndexDrift
+	ValidatorMismatch bool
+	ExpectedValidator bson.M
+	ActualValidator   bson.M
+}
+
+func (sd *SchemaDrift) Empty() bool {
+	return len(sd.MissingIndexes) == 0 &&
+		len(sd.ExtraIndexes) == 0 &&
+		len(sd.ChangedIndexes) == 0 &&
+		!sd.ValidatorMismatch
+}
+
+// Err returns an error matching ErrSchemaDrift that summarizes the drift,
+// or nil when there is none.
+func (sd *SchemaDrift) Err() error {
+	if sd.Empty() {
+		return nil
+	}
+
+	parts := []string{}
+
+	if len(sd.MissingIndexes) > 0 {
+		parts = append(parts, "missing indexes "+strings.Join(sd.MissingIndexes, ", "))
+	}
+
+	if len(sd.ExtraIndexes) > 0 {
+		parts = append(parts, "undeclared indexes "+strings.Join(sd.ExtraIndexes, ", "))
+	}
+
+	for _, changed := range sd.ChangedIndexes {
+		parts = append(parts, fmt.Sprintf("index %s differs in %s", changed.Keys, strings.Join(changed.Options, ", ")))
+	}
+
+	if sd.ValidatorMismatch {
+		parts = append(parts, "validator differs")
+	}
+
+	return fmt.Errorf("%w on %s: %s", ErrSchemaDrift, sd.Collection, strings.Join(parts, "; "))
+}
+
+var indexOptionNames = []string{"unique", "sparse", "expireAfterSeconds", "partialFilterExpression"}
+
+// ValidateSchemaDrift compares the indexes and validator of the
+// collection with those declared by the model through IndexedModel and
+// SchemaModel. Undeclared aspects are not compared.
+func (mr *MongoRepository[T]) ValidateSchemaDrift(ctx context.Context) (*SchemaDrift, error) {
+	coll := mr.collection(ctx)
+	drift := &SchemaDrift{Collection: coll.Name()}
+
+	if indexed, ok := interface{}(mr.Model).(IndexedModel); ok {
+		if err := mr.indexDrift(ctx, coll, indexed.Indexes(), drift); err != nil {
+			return nil, err
+		}
+	}
+
+	if schema, ok := interface{}(mr.Model).(SchemaModel); ok {
+		if err := mr.validatorDrift(ctx, coll, schema.JSONSchema(), drift); err != nil {
+			return nil, err
+		}
+	}
+
+	return drift, nil
+}
+
+func (mr *MongoRepository[T]) indexDrift(
+	ctx context.Context,
+	coll *mongo.Collection,
+	declared []mongo.IndexModel,
+	drift *SchemaDrift,
+) error {
+	cursor, err := coll.Indexes().List(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	var existing []bson.Raw
+
+	if err = cursor.All(ctx, &existing); err != nil {
+		return err
+	}
+
+	actual := map[string]bson.M{}
+
+	for _, index := range existing {
+		if name, _ := index.Lookup("name").StringValueOK(); name == "_id_" {
+			continue
+		}
+
+		keys, ok := index.Lookup("key").DocumentOK()
+
+		if !ok {
+			continue
+		}
+
+		options := bson.M{}
+
+		for _, name := range indexOptionNames {
+			if value, err := index.LookupErr(name); err == nil {
+				options[name] = canonical(value)
+			}
+		}
+
+		actual[keyPattern(keys)] = options
+	}
+
+	for _, model := range declared {
+		raw, err := bson.Marshal(model.Keys)
+
+		if err != nil {
+			return err
+		}
+
+		pattern := keyPattern(raw)
+		options, ok := actual[pattern]
+
+		if !ok {
+			drift.MissingIndexes = append(drift.MissingIndexes, pattern)
+
+			continue
+		}
+
+		delete(actual, pattern)
+
+		if changed := indexOptionDiff(model, options); len(changed) > 0 {
+			drift.ChangedIndexes = append(drift.ChangedIndexes, IndexDrift{Keys: pattern, Options: changed})
+		}
+	}
+
+	for pattern := range actual {
+		drift.ExtraIndexes = append(drift.ExtraIndexes, pattern)
+	}
+
+	sort.Strings(drift.ExtraIndexes)
+
+	return nil
+}
+
+func indexOptionDiff(model mongo.IndexModel, actual bson.M) []string {
+	expected := bson.M{}
+
+	if opts := model.Options; opts != nil {
+		if opts.Unique != nil && *opts.Unique {
+			expected["unique"] = true
+		}
+
+		if opts.Sparse != nil && *opts.Sparse {
+			expected["sparse"] = true
+		}
+
+		if opts.ExpireAfterSeconds != nil {
+			expected["expireAfterSeconds"] = float64(*opts.ExpireAfterSeconds)
+		}
+
+		if opts.PartialFilterExpression != nil {
+			expected["partialFilterExpression"] = canonicalValue(opts.PartialFilterExpression)
+		}
+	}
+
+	changed := []string{}
+
+	for _, name := range indexOptionNames {
+		if !reflect.DeepEqual(expected[name], actual[name]) {
+			changed = append(changed, name)
+		}
+	}
+
+	return changed
+}
+
+func (mr *MongoRepository[T]) validatorDrift(
+	ctx context.Context,
+	coll *mongo.Collection,
+	schema bson.M,
+	drift *SchemaDrift,
+) error {
+	specs, err := coll.Database().ListCollectionSpecifications(ctx, bson.M{"name": coll.Name()})
+
+	if err != nil {
+		return err
+	}
+
+	drift.ExpectedValidator = bson.M{"$jsonSchema": schema}
+
+	if len(specs) > 0 && specs[0].Options != nil {
+		if validator, ok := specs[0].Options.Lookup("validator").DocumentOK(); ok {
+			if err = bson.Unmarshal(validator, &drift.ActualValidator); err != nil {
+				return err
+			}
+		}
+	}
+
+	drift.ValidatorMismatch = !reflect.DeepEqual(
+		canonicalValue(drift.ExpectedValidator),
+		canonicalValue(drift.ActualValidator),
+	)
+
+	return nil
+}
+
+// keyPattern renders index keys the way the server names indexes.
+func keyPattern(keys bson.Raw) string {
+	elements, _ := keys.Elements()
+	parts := make([]string, 0, len(elements)*2)
+
+	for _, element := range elements {
+		parts = append(parts, element.Key(), fmt.Sprint(canonical(element.Value())))
+	}
+
+	return strings.Join(parts, "_")
+}
+
+// canonicalValue converts v into comparable form: documents become
+// bson.M and every number a float64.
+func canonicalValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	t, data, err := bson.MarshalValue(v)
+
+	if err != nil {
+		return v
+	}
+
+	return canonical(bson.RawValue{Type: t, Value: data})
+}
+
+func canonical(value bson.RawValue) interface{} {
+	if number, ok := rawNumber(value); ok {
+		return number
+	}
+
+	switch value.Type {
+	case bson.TypeEmbeddedDocument:
+		elements, _ := value.Document().Elements()
+		doc := bson.M{}
+
+		for _, element := range elements {
+			doc[element.Key()] = canonical(element.Value())
+		}
+
+		return doc
+	case bson.TypeArray:
+		values, _ := value.Array().Values()
+		array := make([]interface{}, 0, len(values))
+
+		for _, item := range values {
+			array = append(array, canonical(item))
+		}
+
+		return array
+	case bson.TypeNull:
+		return nil
+	}
+
+	var out interface{}
+
+	if err := value.Unmarshal(&out); err != nil {
+		return value.String()
+	}
+
+	return out
+}
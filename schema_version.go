@@ -0,0 +1,130 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const SchemaVersionField = "_sv"
+
+// SchemaVersioned models report the schema version they decode. Stored
+// documents with a lower _sv are migrated on read.
+type SchemaVersioned interface {
+	SchemaVersion() int
+}
+
+// SchemaMigration upgrades doc in place from one version to the next.
+type SchemaMigration func(doc bson.M) error
+
+// RegisterMigration registers the upgrade from version from to from+1.
+func (mr *MongoRepository[T]) RegisterMigration(from int, migration SchemaMigration) {
+	if mr.Migrations == nil {
+		mr.Migrations = map[int]SchemaMigration{}
+	}
+
+	mr.Migrations[from] = migration
+}
+
+func (mr *MongoRepository[T]) schemaVersion() (int, bool) {
+	versioned, ok := any(mr.Model).(SchemaVersioned)
+
+	if !ok {
+		return 0, false
+	}
+
+	return versioned.SchemaVersion(), true
+}
+
+// migrate brings raw up to the model schema version. Documents without
+// _sv are treated as version 0.
+func (mr *MongoRepository[T]) migrate(ctx context.Context, raw bson.Raw) (bson.Raw, error) {
+	current, ok := mr.schemaVersion()
+
+	if !ok {
+		return raw, nil
+	}
+
+	stored := 0
+
+	if value, err := raw.LookupErr(SchemaVersionField); err == nil {
+		if v, ok := value.AsInt64OK(); ok {
+			stored = int(v)
+		}
+	}
+
+	if stored >= current {
+		return raw, nil
+	}
+
+	doc := bson.M{}
+
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	for version := stored; version < current; version++ {
+		migration, ok := mr.Migrations[version]
+
+		if !ok {
+			return nil, fmt.Errorf("remongo: no migration registered from schema version %d", version)
+		}
+
+		if err := migration(doc); err != nil {
+			return nil, fmt.Errorf("remongo: schema migration from version %d: %w", version, err)
+		}
+	}
+
+	doc[SchemaVersionField] = current
+
+	migrated, err := bson.Marshal(doc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if mr.WriteBackMigrations {
+		filter := bson.M{"_id": doc["_id"], SchemaVersionField: raw.Lookup(SchemaVersionField)}
+
+		if stored == 0 {
+			filter[SchemaVersionField] = bson.M{"$in": bson.A{nil, 0}}
+		}
+
+		if _, err = mr.collection(ctx).ReplaceOne(ctx, filter, bson.Raw(migrated)); err != nil {
+			return nil, err
+		}
+	}
+
+	return migrated, nil
+}
+
+func (mr *MongoRepository[T]) stampSchemaVersion(model interface{}) (interface{}, error) {
+	current, ok := mr.schemaVersion()
+
+	if !ok {
+		return model, nil
+	}
+
+	raw, err := mr.marshal(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	elements, err := raw.Elements()
+
+	if err != nil {
+		return nil, err
+	}
+
+	doc := bson.D{}
+
+	for _, element := range elements {
+		if element.Key() != SchemaVersionField {
+			doc = append(doc, bson.E{Key: element.Key(), Value: element.Value()})
+		}
+	}
+
+	return append(doc, bson.E{Key: SchemaVersionField, Value: current}), nil
+}
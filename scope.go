@@ -0,0 +1,224 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ScopeProvider returns the filter fragment an operation is restricted
+// to, or nil to leave it unrestricted.
+type ScopeProvider func(ctx context.Context, op *Operation) (interface{}, error)
+
+var (
+	scopesMu sync.RWMutex
+	scopes   = map[string]ScopeProvider{}
+)
+
+// RegisterScope makes provider available to repositories under name,
+// e.g. "owned-by-user".
+func RegisterScope(name string, provider ScopeProvider) {
+	scopesMu.Lock()
+	defer scopesMu.Unlock()
+
+	scopes[name] = provider
+}
+
+// WithScopes restricts the filtered reads, updates and deletes of the
+// repository with the fragments of the named scopes, in addition to its
+// tenant collection: FindOne, Find, Query, FindByIDs, FindKeyset,
+// FindRaw, ForEachBatch, Traverse, Sample, Buckets, AutoBuckets,
+// SumMoney, GroupByTime, ParallelScan, ReplaceOne, the updates, the
+// deletes, ArchiveMany, Anonymize, RetentionBatch and the helpers built
+// on them. Inserts are not filtered; check them with an Authorizer.
+// Neither are BulkWrite, UpsertMany and BulkUpsertStream, whose models
+// carry their own filters, nor Compare, Backfill and Watch, which read
+// the collection directly.
+// With(WithScopes()) returns an unscoped copy.
+func WithScopes(names ...string) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.scopes = append([]string{}, names...)
+	}
+}
+
+// OwnedBy scopes operations to documents whose field holds the actor of
+// ctx and denies them without one.
+func OwnedBy(field string) ScopeProvider {
+	return func(ctx context.Context, op *Operation) (interface{}, error) {
+		actor := ActorFromContext(ctx)
+
+		if actor == "" {
+			return nil, Deny("%s on %s requires an actor", op.Name, op.Collection)
+		}
+
+		return bson.M{field: actor}, nil
+	}
+}
+
+// Scope registers a named filter of the repository, e.g. "active", for
+// Scoped. Register scopes while setting the repository up.
+func (mr *MongoRepository[T]) Scope(name string, filter interface{}) {
+	if mr.NamedScopes == nil {
+		mr.NamedScopes = map[string]interface{}{}
+	}
+
+	mr.NamedScopes[name] = filter
+}
+
+// Scoped returns a copy of the repository whose filtered operations also
+// match every named scope, e.g. repo.Scoped("active", "recent").Find(...).
+func (mr *MongoRepository[T]) Scoped(names ...string) IMongoRepository[T] {
+	clone := *mr
+	clone.scoped = append(append([]string{}, mr.scoped...), names...)
+
+	return &clone
+}
+
+// WithDefaultFilter ANDs filter into every read, update and replace of
+// the repository that scopes apply to, see WithScopes, including the
+// aggregation helpers and Anonymize, e.g. {"status": {"$ne": "archived"}}.
+// Deletes, ArchiveMany and RetentionBatch are not filtered, nor are the
+// bulk writes. Unfiltered bypasses it.
+func WithDefaultFilter(filter bson.D) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.defaultFilter = filter
+	}
+}
+
+// Unfiltered returns a copy of the repository without its default
+// filter.
+func (mr *MongoRepository[T]) Unfiltered() IMongoRepository[T] {
+	clone := *mr
+	clone.DefaultFilter = nil
+
+	return &clone
+}
+
+func (mr *MongoRepository[T]) applyScopes(ctx context.Context, op *Operation) error {
+	defaulted := len(mr.DefaultFilter) > 0 && defaultFilteredOperations[op.Name]
+
+	if len(mr.Scopes) == 0 && len(mr.scoped) == 0 && !defaulted || !scopedOperations[op.Name] {
+		return nil
+	}
+
+	clauses := bson.A{}
+
+	if op.Filter != nil {
+		clauses = append(clauses, op.Filter)
+	}
+
+	if defaulted {
+		clauses = append(clauses, mr.DefaultFilter)
+	}
+
+	for _, name := range mr.scoped {
+		filter, ok := mr.NamedScopes[name]
+
+		if !ok {
+			return fmt.Errorf("remongo: unknown scope %q of %s", name, op.Collection)
+		}
+
+		clauses = append(clauses, filter)
+	}
+
+	for _, name := range mr.Scopes {
+		scopesMu.RLock()
+		provider, ok := scopes[name]
+		scopesMu.RUnlock()
+
+		if !ok {
+			return fmt.Errorf("remongo: unknown scope %q", name)
+		}
+
+		fragment, err := provider(ctx, op)
+
+		if err != nil {
+			return err
+		}
+
+		if fragment != nil {
+			clauses = append(clauses, fragment)
+		}
+	}
+
+	if len(clauses) > 0 {
+		op.Filter = bson.D{{Key: "$and", Value: clauses}}
+	}
+
+	return nil
+}
+
+// restriction returns the filter op is held to by scopes and the default
+// filter alone, or nil when it is not restricted.
+func (mr *MongoRepository[T]) restriction(ctx context.Context, op *Operation) (interface{}, error) {
+	restricted := &Operation{Name: op.Name, Collection: op.Collection}
+
+	if err := mr.applyScopes(ctx, restricted); err != nil {
+		return nil, err
+	}
+
+	return restricted.Filter, nil
+}
+
+// within ANDs scope, if any, into filter.
+func within(filter interface{}, scope interface{}) interface{} {
+	if scope == nil {
+		return filter
+	}
+
+	return bson.D{{Key: "$and", Value: bson.A{filter, scope}}}
+}
+
+var scopedOperations = map[string]bool{
+	"Find":             true,
+	"FindOne":          true,
+	"ReplaceOne":       true,
+	"UpdateOne":        true,
+	"UpdateMany":       true,
+	"DeleteOne":        true,
+	"DeleteMany":       true,
+	"FindOneAndUpdate": true,
+	"Query.All":        true,
+	"Query.One":        true,
+	"Query.Count":      true,
+	"FindByIDs":        true,
+	"FindKeyset":       true,
+	"FindRaw":          true,
+	"ForEachBatch":     true,
+	"Traverse":         true,
+	"Sample":           true,
+	"Buckets":          true,
+	"AutoBuckets":      true,
+	"SumMoney":         true,
+	"GroupByTime":      true,
+	"ParallelScan":     true,
+	"ArchiveMany":      true,
+	"Anonymize":        true,
+	"RetentionBatch":   true,
+}
+
+var defaultFilteredOperations = map[string]bool{
+	"Find":             true,
+	"FindOne":          true,
+	"ReplaceOne":       true,
+	"UpdateOne":        true,
+	"UpdateMany":       true,
+	"FindOneAndUpdate": true,
+	"Query.All":        true,
+	"Query.One":        true,
+	"Query.Count":      true,
+	"FindByIDs":        true,
+	"FindKeyset":       true,
+	"FindRaw":          true,
+	"ForEachBatch":     true,
+	"Traverse":         true,
+	"Sample":           true,
+	"Buckets":          true,
+	"AutoBuckets":      true,
+	"SumMoney":         true,
+	"GroupByTime":      true,
+	"ParallelScan":     true,
+	"Anonymize":        true,
+}
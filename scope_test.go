@@ -0,0 +1,76 @@
+package remongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestScopesRestrictAggregationHelpers(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("sample and buckets", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.orders", mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, "db.orders", mtest.FirstBatch),
+		)
+
+		repo := NewRepository[transitionOrder](mt.DB).(*MongoRepository[transitionOrder])
+		repo.Scope("paid", bson.M{"status": "paid"})
+		scoped := repo.Scoped("paid")
+
+		if _, err := scoped.Sample(context.Background(), bson.M{"_id": 1}, 5); err != nil {
+			mt.Fatal(err)
+		}
+
+		if _, err := scoped.AutoBuckets(context.Background(), "total", 4); err != nil {
+			mt.Fatal(err)
+		}
+
+		events := mt.GetAllStartedEvents()
+
+		if len(events) != 2 {
+			mt.Fatalf("want 2 aggregates, got %d", len(events))
+		}
+
+		for _, event := range events {
+			match, err := event.Command.Lookup("pipeline").Array().Index(0).Value().Document().LookupErr("$match")
+
+			if err != nil {
+				mt.Fatalf("want a leading $match in %v", event.Command)
+			}
+
+			clauses := match.Document().Lookup("$and").Array()
+			values, _ := clauses.Values()
+
+			if status := values[len(values)-1].Document().Lookup("status").StringValue(); status != "paid" {
+				mt.Fatalf("want the paid scope in %v", match)
+			}
+		}
+	})
+}
+
+func TestDefaultFilterRestrictsAggregationHelpers(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("sum money", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.orders", mtest.FirstBatch))
+
+		repo := NewRepository[transitionOrder](mt.DB, WithDefaultFilter(bson.D{
+			{Key: "status", Value: bson.M{"$ne": "archived"}},
+		}))
+
+		if _, err := repo.SumMoney(context.Background(), "total", nil); err != nil {
+			mt.Fatal(err)
+		}
+
+		match := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Index(0).Value().Document().Lookup("$match")
+		values, _ := match.Document().Lookup("$and").Array().Values()
+
+		if _, err := values[len(values)-1].Document().LookupErr("status", "$ne"); err != nil {
+			mt.Fatalf("want the default filter in %v", match)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrMissingShardKey = errors.New("remongo: document is missing the shard key")
+
+// EnableSharding shards the repository collection on keySpec and records
+// the key so writes and queries are checked against it.
+func (mr *MongoRepository[T]) EnableSharding(ctx context.Context, keySpec bson.D) error {
+	admin := mr.Database.Client().Database("admin")
+
+	err := admin.RunCommand(ctx, bson.D{{Key: "enableSharding", Value: mr.Database.Name()}}).Err()
+
+	if err != nil {
+		return err
+	}
+
+	err = admin.RunCommand(ctx, bson.D{
+		{Key: "shardCollection", Value: mr.Database.Name() + "." + mr.CollectionName()},
+		{Key: "key", Value: keySpec},
+	}).Err()
+
+	if err != nil {
+		return err
+	}
+
+	mr.ShardKey = keySpec
+
+	return nil
+}
+
+// checkShardKey rejects documents that do not carry every shard key field.
+func (mr *MongoRepository[T]) checkShardKey(model interface{}) error {
+	if len(mr.ShardKey) == 0 {
+		return nil
+	}
+
+	raw, err := mr.marshal(model)
+
+	if err != nil {
+		return err
+	}
+
+	for _, key := range mr.ShardKey {
+		if _, err = raw.LookupErr(strings.Split(key.Key, ".")...); err != nil {
+			return fmt.Errorf("%w: %s", ErrMissingShardKey, key.Key)
+		}
+	}
+
+	return nil
+}
+
+// checkShardFilter reports queries that cannot be routed to a single
+// shard because their filter lacks a shard key field.
+func (mr *MongoRepository[T]) checkShardFilter(op string, filter interface{}) {
+	if len(mr.ShardKey) == 0 {
+		return
+	}
+
+	doc, err := toFilter(filter)
+
+	if err != nil {
+		return
+	}
+
+	raw, err := bson.Marshal(doc)
+
+	if err != nil {
+		return
+	}
+
+	for _, key := range mr.ShardKey {
+		if _, err = bson.Raw(raw).LookupErr(key.Key); err == nil {
+			continue
+		}
+
+		if mr.OnScatterGather != nil {
+			mr.OnScatterGather(op, mr.CollectionName(), filter)
+		} else {
+			mr.logger().Printf(
+				"remongo: %s on %s without shard key %q is a scatter-gather query",
+				op,
+				mr.CollectionName(),
+				key.Key,
+			)
+		}
+
+		return
+	}
+}
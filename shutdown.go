@@ -0,0 +1,131 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrShuttingDown = errors.New("remongo: shutting down")
+
+// lifecycle tracks everything Shutdown has to wait for.
+var lifecycle = struct {
+	mu       sync.Mutex
+	closing  bool
+	inflight sync.WaitGroup
+	workers  map[*context.CancelFunc]struct{}
+	closers  []func(ctx context.Context) error
+	clients  map[*mongo.Client]struct{}
+}{
+	workers: map[*context.CancelFunc]struct{}{},
+	clients: map[*mongo.Client]struct{}{},
+}
+
+// beginOperation registers an operation that Shutdown waits for.
+func beginOperation() (func(), error) {
+	lifecycle.mu.Lock()
+	defer lifecycle.mu.Unlock()
+
+	if lifecycle.closing {
+		return nil, ErrShuttingDown
+	}
+
+	lifecycle.inflight.Add(1)
+
+	return lifecycle.inflight.Done, nil
+}
+
+// beginWorker registers a long-running loop. Its context is cancelled
+// when Shutdown starts, and Shutdown waits for it to return.
+func beginWorker(ctx context.Context) (context.Context, func(), error) {
+	lifecycle.mu.Lock()
+	defer lifecycle.mu.Unlock()
+
+	if lifecycle.closing {
+		return nil, nil, ErrShuttingDown
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	lifecycle.workers[&cancel] = struct{}{}
+	lifecycle.inflight.Add(1)
+
+	return ctx, func() {
+		lifecycle.mu.Lock()
+		delete(lifecycle.workers, &cancel)
+		lifecycle.mu.Unlock()
+
+		cancel()
+		lifecycle.inflight.Done()
+	}, nil
+}
+
+// registerCloser adds fn to the work done by Shutdown once nothing is in
+// flight anymore, before clients are disconnected.
+func registerCloser(fn func(ctx context.Context) error) {
+	lifecycle.mu.Lock()
+	lifecycle.closers = append(lifecycle.closers, fn)
+	lifecycle.mu.Unlock()
+}
+
+func registerClient(client *mongo.Client) {
+	if client == nil {
+		return
+	}
+
+	lifecycle.mu.Lock()
+	lifecycle.clients[client] = struct{}{}
+	lifecycle.mu.Unlock()
+}
+
+// Shutdown rejects new operations with ErrShuttingDown, stops change
+// stream consumers and schedulers, waits for in-flight operations, flushes
+// write-behind queues and disconnects the clients of every repository.
+// It returns ctx.Err() if draining does not finish in time.
+func Shutdown(ctx context.Context) error {
+	lifecycle.mu.Lock()
+	lifecycle.closing = true
+
+	for cancel := range lifecycle.workers {
+		(*cancel)()
+	}
+
+	lifecycle.mu.Unlock()
+
+	drained := make(chan struct{})
+
+	go func() {
+		lifecycle.inflight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	lifecycle.mu.Lock()
+	closers := lifecycle.closers
+	clients := lifecycle.clients
+	lifecycle.closers = nil
+	lifecycle.clients = map[*mongo.Client]struct{}{}
+	lifecycle.mu.Unlock()
+
+	var errs []error
+
+	for _, closer := range closers {
+		if err := closer(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for client := range clients {
+		if err := client.Disconnect(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,115 @@
+package remongo
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SortDirection int
+
+const (
+	Asc  SortDirection = 1
+	Desc SortDirection = -1
+)
+
+type SortKey struct {
+	Field     string
+	Direction SortDirection
+	NullsLast bool
+}
+
+// Sort is an ordered list of sort keys built with SortBy and ThenBy.
+type Sort []SortKey
+
+func SortBy(field string, direction SortDirection) Sort {
+	return Sort{{Field: field, Direction: direction}}
+}
+
+// ThenBy returns a new Sort, so several can extend the same base.
+func (s Sort) ThenBy(field string, direction SortDirection) Sort {
+	return append(append(Sort{}, s...), SortKey{Field: field, Direction: direction})
+}
+
+// NullsLast moves documents with a missing or null value for the last
+// added key behind all others. Only Stages can express it; Doc keeps the
+// server's default of sorting nulls as the lowest value.
+func (s Sort) NullsLast() Sort {
+	s = append(Sort{}, s...)
+
+	if len(s) > 0 {
+		s[len(s)-1].NullsLast = true
+	}
+
+	return s
+}
+
+func (s Sort) Doc() bson.D {
+	doc := bson.D{}
+
+	for _, key := range s {
+		doc = append(doc, bson.E{Key: key.Field, Value: int(key.Direction)})
+	}
+
+	return doc
+}
+
+// Validate checks every sort key against the fields declared by model.
+func (s Sort) Validate(model interface{}) error {
+	for _, key := range s {
+		if err := ValidatePath(model, key.Field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stages returns aggregation stages implementing the sort, including the
+// nulls-last placement that a plain find cannot express.
+func (s Sort) Stages() mongo.Pipeline {
+	flags := bson.D{}
+	doc := bson.D{}
+
+	for _, key := range s {
+		if key.NullsLast {
+			flag := "__remongo_null_" + strings.ReplaceAll(key.Field, ".", "_")
+			flags = append(flags, bson.E{Key: flag, Value: bson.M{
+				"$eq": bson.A{bson.M{"$ifNull": bson.A{"$" + key.Field, nil}}, nil},
+			}})
+			doc = append(doc, bson.E{Key: flag, Value: 1})
+		}
+
+		doc = append(doc, bson.E{Key: key.Field, Value: int(key.Direction)})
+	}
+
+	if len(flags) == 0 {
+		return mongo.Pipeline{{{Key: "$sort", Value: doc}}}
+	}
+
+	unset := bson.A{}
+
+	for _, flag := range flags {
+		unset = append(unset, flag.Key)
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$addFields", Value: flags}},
+		{{Key: "$sort", Value: doc}},
+		{{Key: "$unset", Value: unset}},
+	}
+}
+
+func (s Sort) FindOptions() *options.FindOptions {
+	return options.Find().SetSort(s.Doc())
+}
+
+func (s Sort) FindOneOptions() *options.FindOneOptions {
+	return options.FindOne().SetSort(s.Doc())
+}
+
+func (s Sort) FindOneAndUpdateOptions() *options.FindOneAndUpdateOptions {
+	return options.FindOneAndUpdate().SetSort(s.Doc())
+}
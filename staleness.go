@@ -0,0 +1,159 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+var ErrStaleRead = errors.New("remongo: replication lag exceeds max staleness")
+
+// StalePolicy decides what a read does when replication lag exceeds
+// MaxStaleness.
+type StalePolicy int
+
+const (
+	// StaleFail returns ErrStaleRead.
+	StaleFail StalePolicy = iota
+	// StaleFallbackPrimary sends the read to the primary instead.
+	StaleFallbackPrimary
+)
+
+// lagSampleInterval bounds how often replSetGetStatus is run per client.
+const lagSampleInterval = 5 * time.Second
+
+// minDriverStaleness is the smallest max staleness the driver accepts.
+const minDriverStaleness = 90 * time.Second
+
+// WithMaxStaleness rejects secondary reads while the replication lag,
+// sampled with replSetGetStatus, exceeds maxStaleness. A lag that cannot
+// be sampled, e.g. through mongos or without the privilege, counts as
+// exceeding it. Values of 90s or more are also passed to the driver's
+// server selection.
+func WithMaxStaleness(maxStaleness time.Duration, policy StalePolicy) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.maxStaleness = maxStaleness
+		c.stalePolicy = policy
+
+		if maxStaleness >= minDriverStaleness {
+			c.readOptions = append(c.readOptions, readpref.WithMaxStaleness(maxStaleness))
+		}
+	}
+}
+
+// WithMetrics reports the observed replication lag of secondary reads
+// as the "replication_lag" metric.
+func WithMetrics(hook MetricsHook) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.metrics = hook
+	}
+}
+
+type primaryKey struct{}
+
+func contextWithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func forcePrimary(ctx context.Context) bool {
+	forced, _ := ctx.Value(primaryKey{}).(bool)
+
+	return forced
+}
+
+// checkStaleness runs before reads that may go to a secondary. With the
+// fallback policy it returns a context that routes them to the primary.
+func (mr *MongoRepository[T]) checkStaleness(ctx context.Context, op *Operation) (context.Context, error) {
+	if writeOperations[op.Name] || mr.ReadPreference == nil || mr.ReadPreference.Mode() == readpref.PrimaryMode {
+		return ctx, nil
+	}
+
+	if mr.MaxStaleness <= 0 && mr.Metrics == nil {
+		return ctx, nil
+	}
+
+	lag, err := replicationLag(ctx, mr.Database.Client())
+
+	if mr.Metrics != nil {
+		mr.Metrics(Metric{Name: "replication_lag", Duration: lag, Err: err})
+	}
+
+	if mr.MaxStaleness <= 0 || err == nil && lag <= mr.MaxStaleness {
+		return ctx, nil
+	}
+
+	if mr.StalePolicy == StaleFallbackPrimary {
+		return contextWithPrimary(ctx), nil
+	}
+
+	if err != nil {
+		return ctx, fmt.Errorf("%w: replication lag unknown: %v", ErrStaleRead, err)
+	}
+
+	return ctx, ErrStaleRead
+}
+
+type lagSample struct {
+	lag time.Duration
+	err error
+	at  time.Time
+}
+
+var lagSamples = struct {
+	sync.Mutex
+	byClient map[*mongo.Client]lagSample
+}{byClient: map[*mongo.Client]lagSample{}}
+
+// replicationLag returns how far the most lagging secondary is behind
+// the primary.
+func replicationLag(ctx context.Context, client *mongo.Client) (time.Duration, error) {
+	lagSamples.Lock()
+	sample, ok := lagSamples.byClient[client]
+	lagSamples.Unlock()
+
+	if ok && time.Since(sample.at) < lagSampleInterval {
+		return sample.lag, sample.err
+	}
+
+	var status struct {
+		Members []struct {
+			State      int       `bson:"state"`
+			OptimeDate time.Time `bson:"optimeDate"`
+		} `bson:"members"`
+	}
+
+	sample = lagSample{at: time.Now()}
+	sample.err = client.Database("admin").RunCommand(ctx, bson.D{{Key: "replSetGetStatus", Value: 1}}).Decode(&status)
+
+	if sample.err == nil {
+		const primary, secondary = 1, 2
+		var primaryOptime, oldest time.Time
+
+		for _, member := range status.Members {
+			switch member.State {
+			case primary:
+				primaryOptime = member.OptimeDate
+			case secondary:
+				if oldest.IsZero() || member.OptimeDate.Before(oldest) {
+					oldest = member.OptimeDate
+				}
+			}
+		}
+
+		if !primaryOptime.IsZero() && !oldest.IsZero() && primaryOptime.After(oldest) {
+			sample.lag = primaryOptime.Sub(oldest)
+		}
+	}
+
+	lagSamples.Lock()
+	lagSamples.byClient[client] = sample
+	lagSamples.Unlock()
+
+	return sample.lag, sample.err
+}
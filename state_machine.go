@@ -0,0 +1,169 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalidTransition = errors.New("remongo: invalid state transition")
+
+// TransitionError is returned by TransitionTo when the transition is not
+// declared or the document is not in the From state. Current is the
+// state found, if the document was read. It matches ErrInvalidTransition.
+type TransitionError struct {
+	Field   string
+	From    string
+	To      string
+	Current string
+}
+
+func (e *TransitionError) Error() string {
+	if e.Current != "" {
+		return fmt.Sprintf("remongo: cannot move %s from %q to %q: state is %q", e.Field, e.From, e.To, e.Current)
+	}
+
+	return fmt.Sprintf("remongo: %s may not move from %q to %q", e.Field, e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// TransitionField declares the states of a field and the transitions
+// allowed between them.
+type TransitionField struct {
+	Field   string
+	allowed map[string]map[string]bool
+}
+
+func NewTransitionField(field string) *TransitionField {
+	return &TransitionField{Field: field, allowed: map[string]map[string]bool{}}
+}
+
+// Allow permits moving from one state to each of to.
+func (tf *TransitionField) Allow(from string, to ...string) *TransitionField {
+	if tf.allowed[from] == nil {
+		tf.allowed[from] = map[string]bool{}
+	}
+
+	for _, state := range to {
+		tf.allowed[from][state] = true
+	}
+
+	return tf
+}
+
+func (tf *TransitionField) Allowed(from string, to string) bool {
+	return tf.allowed[from][to]
+}
+
+// WithTransitions guards the state field of the repository for
+// TransitionTo.
+func WithTransitions(field *TransitionField) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.transitions = field
+	}
+}
+
+// TransitionTo moves the document id from state from to state to, only
+// if that transition is declared and the document is still in from, and
+// returns the updated document.
+func (mr *MongoRepository[T]) TransitionTo(ctx context.Context, id interface{}, from string, to string) (*T, error) {
+	if mr.Transitions == nil {
+		return nil, errors.New("remongo: no transitions declared, see WithTransitions")
+	}
+
+	field := mr.Transitions.Field
+	transition := &TransitionError{Field: field, From: from, To: to}
+
+	if !mr.Transitions.Allowed(from, to) {
+		return nil, transition
+	}
+
+	filter := bson.D{{Key: "_id", Value: id}, {Key: field, Value: from}}
+
+	return mr.findOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{field: to}}, func(ctx context.Context, scope interface{}) error {
+		return mr.transitionFailure(ctx, within(bson.M{"_id": id}, scope), transition)
+	})
+}
+
+// findOneAndUpdate applies a guarded update and returns the updated
+// document, or the error of miss when filter matched nothing. miss gets
+// the scopes the operation is held to, to look the document up within.
+func (mr *MongoRepository[T]) findOneAndUpdate(
+	ctx context.Context,
+	filter interface{},
+	update interface{},
+	miss func(ctx context.Context, scope interface{}) error,
+) (*T, error) {
+	clone := *mr
+	clone.ctx = ctx
+	model := new(T)
+
+	err := clone.run("FindOneAndUpdate", filter, update, func(ctx context.Context, op *Operation) error {
+		query, err := toFilter(op.Filter)
+
+		if err != nil {
+			return err
+		}
+
+		var raw bson.Raw
+
+		err = mr.write(ctx, Unsafe, func() (err error) {
+			raw, err = mr.collection(ctx).FindOneAndUpdate(
+				ctx,
+				query,
+				op.Update,
+				options.FindOneAndUpdate().SetReturnDocument(options.After),
+			).Raw()
+
+			return err
+		})
+
+		if errors.Is(err, ErrNotFound) {
+			scope, err := mr.restriction(ctx, op)
+
+			if err != nil {
+				return err
+			}
+
+			return miss(ctx, scope)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return mr.decode(ctx, raw, model)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// transitionFailure tells a missing document from one in another state.
+func (mr *MongoRepository[T]) transitionFailure(ctx context.Context, filter interface{}, transition *TransitionError) error {
+	raw, err := mr.collection(ctx).FindOne(
+		ctx,
+		filter,
+		options.FindOne().SetProjection(bson.M{transition.Field: 1}),
+	).Raw()
+
+	if err != nil {
+		return err
+	}
+
+	if value, err := raw.LookupErr(strings.Split(transition.Field, ".")...); err == nil {
+		transition.Current, _ = value.StringValueOK()
+	}
+
+	return transition
+}
@@ -0,0 +1,49 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+type transitionOrder struct {
+	ID     int    `bson:"_id"`
+	Status string `bson:"status"`
+}
+
+func (transitionOrder) Collection() string {
+	return "orders"
+}
+
+func TestTransitionToWrongState(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("wrong state", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+			mtest.CreateCursorResponse(0, "db.orders", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: 1},
+				{Key: "status", Value: "shipped"},
+			}),
+		)
+
+		repo := NewRepository[transitionOrder](mt.DB, WithTransitions(
+			NewTransitionField("status").Allow("pending", "paid"),
+		)).(*MongoRepository[transitionOrder])
+
+		_, err := repo.TransitionTo(context.Background(), 1, "pending", "paid")
+
+		if !errors.Is(err, ErrInvalidTransition) {
+			mt.Fatalf("want ErrInvalidTransition, got %v", err)
+		}
+
+		var transition *TransitionError
+
+		if !errors.As(err, &transition) || transition.Current != "shipped" {
+			mt.Fatalf("want current state shipped, got %v", err)
+		}
+	})
+}
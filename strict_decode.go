@@ -0,0 +1,195 @@
+package remongo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// DecodeMode controls how documents that do not match the model decode.
+type DecodeMode int
+
+const (
+	// DecodeDefault ignores unknown fields and leaves missing ones zero.
+	DecodeDefault DecodeMode = iota
+	// DecodeStrict fails with a *DecodeMismatchError.
+	DecodeStrict
+	// DecodeWarn logs the mismatch and decodes anyway.
+	DecodeWarn
+	// DecodeLenient loads the fields that decode; see WithLenientDecode.
+	DecodeLenient
+)
+
+// WithDecodeMode makes reads report documents with fields the model
+// does not declare, or without fields the model always writes (those
+// not tagged omitempty), to catch schema drift early.
+func WithDecodeMode(mode DecodeMode) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.decodeMode = mode
+	}
+}
+
+// DecodeMismatchError lists dotted paths of unknown and missing fields.
+// It matches ErrSchemaDrift with errors.Is.
+type DecodeMismatchError struct {
+	Type    string
+	Unknown []string
+	Missing []string
+}
+
+func (e *DecodeMismatchError) Error() string {
+	parts := []string{}
+
+	if len(e.Unknown) > 0 {
+		parts = append(parts, "unknown fields "+strings.Join(e.Unknown, ", "))
+	}
+
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing fields "+strings.Join(e.Missing, ", "))
+	}
+
+	return fmt.Sprintf("remongo: document does not match %s: %s", e.Type, strings.Join(parts, "; "))
+}
+
+func (e *DecodeMismatchError) Is(target error) bool {
+	return target == ErrSchemaDrift
+}
+
+func (mr *MongoRepository[T]) checkStrict(raw bson.Raw, model *T) error {
+	if mr.DecodeMode != DecodeStrict && mr.DecodeMode != DecodeWarn {
+		return nil
+	}
+
+	t := reflect.TypeOf(model).Elem()
+	mismatch := &DecodeMismatchError{Type: t.String(), Unknown: []string{}, Missing: []string{}}
+	compareDocument(raw, t, "", mismatch)
+	mismatch.Unknown = mr.withoutInternal(mismatch.Unknown)
+
+	if len(mismatch.Unknown) == 0 && len(mismatch.Missing) == 0 {
+		return nil
+	}
+
+	if mr.DecodeMode == DecodeWarn {
+		mr.logger().Printf("%v", mismatch)
+
+		return nil
+	}
+
+	return mismatch
+}
+
+// internalFields are stored by the package next to the model fields.
+var internalFields = map[string]bool{
+	SchemaVersionField: true,
+	TrashedAtField:     true,
+	LeaseHolderField:   true,
+	LeaseExpiresField:  true,
+	PendingField:       true,
+}
+
+// withoutInternal drops the internal fields and the old names of renamed
+// fields from the unknown paths.
+func (mr *MongoRepository[T]) withoutInternal(paths []string) []string {
+	renamed := map[string]bool{}
+
+	for _, rename := range mr.Renames {
+		renamed[rename.Old] = true
+	}
+
+	kept := []string{}
+
+	for _, path := range paths {
+		if !internalFields[path] && !renamed[path] {
+			kept = append(kept, path)
+		}
+	}
+
+	return kept
+}
+
+func compareDocument(raw bson.Raw, t reflect.Type, prefix string, mismatch *DecodeMismatchError) {
+	t = indirectType(t)
+
+	if t.Kind() != reflect.Struct || t.PkgPath() == "time" || t.PkgPath() == "go.mongodb.org/mongo-driver/bson/primitive" {
+		return
+	}
+
+	fields := map[string]bsonField{}
+
+	for _, field := range bsonFields(t) {
+		fields[field.Name] = field
+	}
+
+	elements, err := raw.Elements()
+
+	if err != nil {
+		return
+	}
+
+	present := map[string]bool{}
+	catchAll := hasInlineMap(t)
+
+	for _, element := range elements {
+		key := element.Key()
+		present[key] = true
+		field, ok := fields[key]
+
+		if !ok {
+			if !catchAll {
+				mismatch.Unknown = append(mismatch.Unknown, prefix+key)
+			}
+
+			continue
+		}
+
+		compareValue(element.Value(), field.Type, prefix+key, mismatch)
+	}
+
+	for _, field := range bsonFields(t) {
+		if !field.OmitEmpty && !present[field.Name] && field.Type.Kind() != reflect.Map {
+			mismatch.Missing = append(mismatch.Missing, prefix+field.Name)
+		}
+	}
+}
+
+func compareValue(value bson.RawValue, t reflect.Type, path string, mismatch *DecodeMismatchError) {
+	t = indirectType(t)
+
+	switch value.Type {
+	case bsontype.EmbeddedDocument:
+		compareDocument(value.Document(), t, path+".", mismatch)
+	case bsontype.Array:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+
+		values, err := value.Array().Values()
+
+		if err != nil {
+			return
+		}
+
+		for i, item := range values {
+			if item.Type == bsontype.EmbeddedDocument {
+				compareDocument(item.Document(), t.Elem(), fmt.Sprintf("%s.%d.", path, i), mismatch)
+			}
+		}
+	}
+}
+
+// hasInlineMap reports a `bson:",inline"` map, which absorbs unknown
+// fields.
+func hasInlineMap(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		if sf.Type.Kind() == reflect.Map && strings.Contains(sf.Tag.Get("bson"), "inline") {
+			return true
+		}
+	}
+
+	return false
+}
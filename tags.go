@@ -0,0 +1,290 @@
+package remongo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TagIssue is one problem found by CheckTags. Field is the dotted Go
+// path of the field.
+type TagIssue struct {
+	Type    string
+	Field   string
+	Problem string
+}
+
+func (ti TagIssue) Error() string {
+	return fmt.Sprintf("%s.%s: %s", ti.Type, ti.Field, ti.Problem)
+}
+
+type taggedField struct {
+	goPath    string
+	bsonName  string
+	jsonName  string
+	hasBSON   bool
+	hasJSON   bool
+	omitEmpty bool
+	sf        reflect.StructField
+}
+
+const maxTagDepth = 8
+
+// CheckTags reports fields of models, including nested structs, whose
+// bson or json tag is missing, whose names differ between the two, and
+// pointer fields whose bson tag lacks omitempty. An _id bson name may
+// pair with an id json name.
+func CheckTags(models ...interface{}) []TagIssue {
+	issues := []TagIssue{}
+
+	for _, model := range models {
+		t := indirectType(reflect.TypeOf(model))
+
+		if t.Kind() != reflect.Struct {
+			continue
+		}
+
+		var check func(st reflect.Type, prefix string, depth int)
+
+		check = func(st reflect.Type, prefix string, depth int) {
+			walkTags(st, prefix, depth, func(field taggedField) {
+				report := func(problem string) {
+					issues = append(issues, TagIssue{Type: t.Name(), Field: field.goPath, Problem: problem})
+				}
+
+				if !field.hasBSON {
+					report("missing bson tag")
+				}
+
+				if !field.hasJSON {
+					report("missing json tag")
+				}
+
+				if field.hasBSON && field.hasJSON && field.jsonName != "-" && field.bsonName != "-" && !sameTagName(field.bsonName, field.jsonName) {
+					report(fmt.Sprintf("bson name %q differs from json name %q", field.bsonName, field.jsonName))
+				}
+
+				if field.sf.Type.Kind() == reflect.Ptr && !field.omitEmpty {
+					report("pointer without omitempty")
+				}
+
+				if nested, ok := nestedStruct(field.sf.Type); ok {
+					check(nested, field.goPath+".", depth+1)
+				}
+			})
+		}
+
+		check(t, "", 0)
+	}
+
+	return issues
+}
+
+func sameTagName(bsonName string, jsonName string) bool {
+	return bsonName == jsonName || bsonName == "_id" && jsonName == "id"
+}
+
+// walkTags visits the fields of t, flattening inline structs.
+func walkTags(t reflect.Type, prefix string, depth int, visit func(taggedField)) {
+	if depth > maxTagDepth {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		bsonTag, hasBSON := sf.Tag.Lookup("bson")
+		jsonTag, hasJSON := sf.Tag.Lookup("json")
+
+		if bsonTag == "-" && (jsonTag == "-" || !hasJSON) {
+			continue
+		}
+
+		bsonName, bsonFlags := parseTag(bsonTag)
+		jsonName, _ := parseTag(jsonTag)
+		nested := elementType(sf.Type)
+
+		if sf.Anonymous && nested.Kind() == reflect.Struct && (strings.Contains(bsonFlags, "inline") || !hasBSON && !hasJSON) {
+			walkTags(nested, prefix, depth+1, visit)
+
+			continue
+		}
+
+		if bsonName == "" {
+			bsonName = strings.ToLower(sf.Name)
+		}
+
+		if jsonName == "" {
+			jsonName = sf.Name
+		}
+
+		visit(taggedField{
+			goPath:    prefix + sf.Name,
+			bsonName:  bsonName,
+			jsonName:  jsonName,
+			hasBSON:   hasBSON,
+			hasJSON:   hasJSON,
+			omitEmpty: strings.Contains(bsonFlags, "omitempty"),
+			sf:        sf,
+		})
+	}
+}
+
+// nestedStruct returns the struct type stored in a field, through
+// pointers and slices, unless it is encoded as a single value.
+func nestedStruct(t reflect.Type) (reflect.Type, bool) {
+	t = elementType(t)
+
+	if t.Kind() != reflect.Struct || t.PkgPath() == "time" || t.PkgPath() == "go.mongodb.org/mongo-driver/bson/primitive" {
+		return nil, false
+	}
+
+	return t, true
+}
+
+func parseTag(tag string) (string, string) {
+	name, flags, _ := strings.Cut(tag, ",")
+
+	return name, flags
+}
+
+// FieldMapper translates the JSON field names of an API into the bson
+// paths of a model, e.g. "address.zipCode" into "address.zip_code".
+type FieldMapper struct {
+	paths map[string]string
+}
+
+func NewFieldMapper(model interface{}) *FieldMapper {
+	fm := &FieldMapper{paths: map[string]string{}}
+	t := indirectType(reflect.TypeOf(model))
+
+	if t.Kind() == reflect.Struct {
+		fm.collect(t, "", "", 0)
+	}
+
+	return fm
+}
+
+func (fm *FieldMapper) collect(t reflect.Type, jsonPrefix string, bsonPrefix string, depth int) {
+	walkTags(t, "", depth, func(field taggedField) {
+		if field.jsonName == "-" || field.bsonName == "-" {
+			return
+		}
+
+		jsonPath, bsonPath := jsonPrefix+field.jsonName, bsonPrefix+field.bsonName
+		fm.paths[jsonPath] = bsonPath
+
+		if nested, ok := nestedStruct(field.sf.Type); ok && depth < maxTagDepth {
+			fm.collect(nested, jsonPath+".", bsonPath+".", depth+1)
+		}
+	})
+}
+
+// BSONPath maps a dotted JSON path. Numeric segments addressing array
+// elements are kept as they are.
+func (fm *FieldMapper) BSONPath(jsonPath string) (string, bool) {
+	segments := strings.Split(jsonPath, ".")
+	fields := make([]string, 0, len(segments))
+	indexes := map[int]string{}
+
+	for i, segment := range segments {
+		if isIndex(segment) {
+			indexes[i] = segment
+		} else {
+			fields = append(fields, segment)
+		}
+	}
+
+	path, ok := fm.paths[strings.Join(fields, ".")]
+
+	if !ok {
+		return "", false
+	}
+
+	if len(indexes) == 0 {
+		return path, true
+	}
+
+	mapped := strings.Split(path, ".")
+	result := make([]string, 0, len(segments))
+
+	for i := range segments {
+		if index, ok := indexes[i]; ok {
+			result = append(result, index)
+		} else {
+			result = append(result, mapped[0])
+			mapped = mapped[1:]
+		}
+	}
+
+	return strings.Join(result, "."), true
+}
+
+// TranslateFilter maps the field names of an API filter, descending into
+// $and, $or and $nor. Unknown fields are an error so that clients cannot
+// query fields the API does not expose.
+func (fm *FieldMapper) TranslateFilter(filter map[string]interface{}) (bson.M, error) {
+	translated := bson.M{}
+
+	for key, value := range filter {
+		if key == "$and" || key == "$or" || key == "$nor" {
+			clauses, ok := value.([]interface{})
+
+			if !ok {
+				return nil, fmt.Errorf("remongo: %s expects an array", key)
+			}
+
+			mapped := bson.A{}
+
+			for _, clause := range clauses {
+				doc, ok := clause.(map[string]interface{})
+
+				if !ok {
+					return nil, fmt.Errorf("remongo: %s expects documents", key)
+				}
+
+				sub, err := fm.TranslateFilter(doc)
+
+				if err != nil {
+					return nil, err
+				}
+
+				mapped = append(mapped, sub)
+			}
+
+			translated[key] = mapped
+
+			continue
+		}
+
+		path, ok := fm.BSONPath(key)
+
+		if !ok {
+			return nil, fmt.Errorf("remongo: unknown filter field %q", key)
+		}
+
+		translated[path] = value
+	}
+
+	return translated, nil
+}
+
+func isIndex(segment string) bool {
+	if segment == "" {
+		return false
+	}
+
+	for _, r := range segment {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
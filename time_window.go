@@ -0,0 +1,148 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Between matches field in [from, to).
+func Between(field string, from time.Time, to time.Time) bson.M {
+	return bson.M{field: bson.M{"$gte": from, "$lt": to}}
+}
+
+// Today matches the current calendar day in loc, or UTC when loc is nil.
+func Today(field string, loc *time.Location) bson.M {
+	start := startOfDay(time.Now(), loc)
+
+	return Between(field, start, start.AddDate(0, 0, 1))
+}
+
+// ThisWeek matches the current week in loc, starting on Monday.
+func ThisWeek(field string, loc *time.Location) bson.M {
+	start := startOfDay(time.Now(), loc)
+	start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+
+	return Between(field, start, start.AddDate(0, 0, 7))
+}
+
+// LastNDays matches the n calendar days in loc up to and including today.
+func LastNDays(field string, n int, loc *time.Location) bson.M {
+	end := startOfDay(time.Now(), loc).AddDate(0, 0, 1)
+
+	return Between(field, end.AddDate(0, 0, -n), end)
+}
+
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	t = t.In(loc)
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+type Granularity string
+
+const (
+	Minute  Granularity = "minute"
+	Hour    Granularity = "hour"
+	Day     Granularity = "day"
+	Week    Granularity = "week"
+	Month   Granularity = "month"
+	Quarter Granularity = "quarter"
+	Year    Granularity = "year"
+)
+
+type TimeBucket struct {
+	Start time.Time `bson:"_id"`
+	Count int64     `bson:"count"`
+}
+
+// GroupByTime counts the documents matching filter per granularity of
+// field with $dateTrunc, computed in loc or UTC when loc is nil. Weeks
+// start on Monday.
+func (mr *MongoRepository[T]) GroupByTime(
+	ctx context.Context,
+	field string,
+	granularity Granularity,
+	filter interface{},
+	loc *time.Location,
+) ([]TimeBucket, error) {
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	trunc := bson.M{"date": "$" + field, "unit": granularity, "timezone": timezoneName(loc)}
+
+	if granularity == Week {
+		trunc["startOfWeek"] = "monday"
+	}
+
+	buckets := []TimeBucket{}
+
+	err = mr.aggregateMatching(ctx, "GroupByTime", query, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$dateTrunc": trunc},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}, &buckets)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// timezoneName returns the name $dateTrunc accepts for loc. "Local" is
+// resolved through TZ or /etc/localtime, and failing that replaced by the
+// current UTC offset of loc.
+func timezoneName(loc *time.Location) string {
+	name := loc.String()
+
+	if name != "Local" {
+		return name
+	}
+
+	candidates := []string{strings.TrimPrefix(os.Getenv("TZ"), ":")}
+
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if _, zone, found := strings.Cut(filepath.ToSlash(target), "zoneinfo/"); found {
+			candidates = append(candidates, zone)
+		}
+	}
+
+	for _, candidate := range candidates {
+		if candidate == "" || candidate == "Local" {
+			continue
+		}
+
+		if _, err := time.LoadLocation(candidate); err == nil {
+			return candidate
+		}
+	}
+
+	_, offset := time.Now().In(loc).Zone()
+	sign := '+'
+
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+
+	return fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset%3600/60)
+}
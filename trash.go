@@ -0,0 +1,232 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrNoTrash = errors.New("remongo: trash is not configured")
+
+// TrashedAtField stamps documents in the trash; its TTL index purges them.
+const TrashedAtField = "_trashed_at"
+
+// TrashOptions turns deletes into moves to Collection, from where
+// documents can be restored until they expire after TTL.
+type TrashOptions struct {
+	Collection string
+	TTL        time.Duration
+}
+
+// WithTrash moves deleted documents to collection, where they are kept
+// for ttl once EnsureTrash has created the expiry index.
+func WithTrash(collection string, ttl time.Duration) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.trash = &TrashOptions{Collection: collection, TTL: ttl}
+	}
+}
+
+func (mr *MongoRepository[T]) trash() *mongo.Collection {
+	return mr.Database.Collection(mr.Trash.Collection)
+}
+
+// EnsureTrash creates the TTL index that expires trashed documents.
+func (mr *MongoRepository[T]) EnsureTrash(ctx context.Context) error {
+	if mr.Trash == nil {
+		return nil
+	}
+
+	_, err := mr.trash().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: TrashedAtField, Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(mr.Trash.TTL.Seconds())),
+	})
+
+	return err
+}
+
+// moveToTrash moves up to limit documents matching filter, or all of
+// them when limit is 0, honoring the collation and hint of opts.
+func (mr *MongoRepository[T]) moveToTrash(
+	ctx context.Context,
+	filter interface{},
+	limit int64,
+	opts []*options.DeleteOptions,
+) (int64, error) {
+	find := options.Find()
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		if opt.Collation != nil {
+			find.SetCollation(opt.Collation)
+		}
+
+		if opt.Hint != nil {
+			find.SetHint(opt.Hint)
+		}
+
+		if comment, ok := opt.Comment.(string); ok {
+			find.SetComment(comment)
+		}
+
+		if opt.Let != nil {
+			find.SetLet(opt.Let)
+		}
+	}
+
+	return mr.transfer(ctx, mr.collection(ctx), mr.trash(), filter, limit, find, func(doc bson.D) bson.D {
+		return append(doc, bson.E{Key: TrashedAtField, Value: time.Now()})
+	})
+}
+
+// RestoreFromTrash moves the trashed documents matching filter back into
+// the collection.
+func (mr *MongoRepository[T]) RestoreFromTrash(ctx context.Context, filter interface{}) (int64, error) {
+	if mr.Trash == nil {
+		return 0, ErrNoTrash
+	}
+
+	var restored int64
+
+	err := mr.runContext(ctx, "RestoreFromTrash", filter, nil, func(ctx context.Context, op *Operation) (err error) {
+		restored, err = mr.transfer(ctx, mr.trash(), mr.collection(ctx), op.Filter, 0, options.Find(), func(doc bson.D) bson.D {
+			return doc
+		})
+
+		return err
+	})
+
+	return restored, err
+}
+
+// PurgeTrash permanently deletes the trashed documents matching filter.
+func (mr *MongoRepository[T]) PurgeTrash(ctx context.Context, filter interface{}) (int64, error) {
+	if mr.Trash == nil {
+		return 0, ErrNoTrash
+	}
+
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int64
+
+	err = mr.runContext(ctx, "PurgeTrash", query, nil, func(ctx context.Context, op *Operation) error {
+		result, err := mr.trash().DeleteMany(ctx, op.Filter)
+
+		if err == nil {
+			purged = result.DeletedCount
+		}
+
+		return err
+	})
+
+	return purged, err
+}
+
+// transfer moves documents between collections, replacing any copy
+// already present in the target, in a transaction where the deployment
+// supports them. Without one, documents are copied before they are
+// deleted, so a failure leaves them in both collections, never in none.
+func (mr *MongoRepository[T]) transfer(
+	ctx context.Context,
+	from *mongo.Collection,
+	to *mongo.Collection,
+	filter interface{},
+	limit int64,
+	find *options.FindOptions,
+	prepare func(doc bson.D) bson.D,
+) (int64, error) {
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if limit > 0 {
+		find.SetLimit(limit)
+	}
+
+	move := func(ctx context.Context) (int64, error) {
+		cursor, err := from.Find(ctx, query, find)
+
+		if err != nil {
+			return 0, err
+		}
+
+		var docs []bson.D
+
+		if err = cursor.All(ctx, &docs); err != nil {
+			return 0, err
+		}
+
+		ids := bson.A{}
+
+		for _, doc := range docs {
+			copied := bson.D{}
+
+			for _, e := range doc {
+				if e.Key != TrashedAtField {
+					copied = append(copied, e)
+				}
+			}
+
+			id := doc.Map()["_id"]
+			ids = append(ids, id)
+
+			_, err = to.ReplaceOne(ctx, bson.M{"_id": id}, prepare(copied), options.Replace().SetUpsert(true))
+
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		if len(ids) == 0 {
+			return 0, nil
+		}
+
+		result, err := from.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+
+		if err != nil {
+			return 0, err
+		}
+
+		return result.DeletedCount, nil
+	}
+
+	transactional, err := supportsTransactions(ctx, mr.Database.Client())
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !transactional {
+		return move(ctx)
+	}
+
+	session, err := mr.Database.Client().StartSession()
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer session.EndSession(ctx)
+
+	moved, err := session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return move(sc)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return moved.(int64), nil
+}
@@ -0,0 +1,178 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	ParentField    = "parent"
+	AncestorsField = "ancestors"
+	DepthField     = "depth"
+)
+
+// TreeNode stores the place of a document in a hierarchy such as
+// categories or an org chart: its parent, the _ids from the root down to
+// the parent, and its depth, 0 for roots. Embed it with `bson:",inline"`.
+type TreeNode struct {
+	Parent    interface{}   `bson:"parent"`
+	Ancestors []interface{} `bson:"ancestors"`
+	Depth     int           `bson:"depth"`
+}
+
+// SetParent places the node under the node parent whose _id is
+// parentID, or at the root when parent is nil. Use MoveSubtree for
+// stored nodes with children.
+func (tn *TreeNode) SetParent(parentID interface{}, parent *TreeNode) {
+	if parent == nil {
+		*tn = TreeNode{Ancestors: []interface{}{}}
+
+		return
+	}
+
+	tn.Parent = parentID
+	tn.Ancestors = append(append([]interface{}{}, parent.Ancestors...), parentID)
+	tn.Depth = parent.Depth + 1
+}
+
+// EnsureTreeIndexes creates the indexes of the tree lookups.
+func (mr *MongoRepository[T]) EnsureTreeIndexes(ctx context.Context) error {
+	_, err := mr.collection(ctx).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: AncestorsField, Value: 1}, {Key: DepthField, Value: 1}}},
+		{Keys: bson.D{{Key: ParentField, Value: 1}}},
+	})
+
+	return err
+}
+
+func (mr *MongoRepository[T]) treeNode(ctx context.Context, id interface{}) (*TreeNode, error) {
+	var node TreeNode
+
+	err := mr.collection(ctx).FindOne(ctx, bson.M{"_id": id}).Decode(&node)
+
+	if err != nil {
+		return nil, mr.wrapError("FindOne", bson.M{"_id": id}, err)
+	}
+
+	return &node, nil
+}
+
+func (mr *MongoRepository[T]) FindChildren(ctx context.Context, id interface{}) ([]T, error) {
+	return mr.Query().Where(bson.M{ParentField: id}).All(ctx)
+}
+
+// FindDescendants returns the subtree below id, at most maxDepth levels
+// deep, or all of it when maxDepth is 0, ordered by depth.
+func (mr *MongoRepository[T]) FindDescendants(ctx context.Context, id interface{}, maxDepth int) ([]T, error) {
+	filter := bson.M{AncestorsField: id}
+
+	if maxDepth > 0 {
+		node, err := mr.treeNode(ctx, id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		filter[DepthField] = bson.M{"$lte": node.Depth + maxDepth}
+	}
+
+	return mr.Query().Where(filter).Sort(SortBy(DepthField, Asc)).All(ctx)
+}
+
+// FindAncestors returns the nodes from the root down to the parent of id.
+func (mr *MongoRepository[T]) FindAncestors(ctx context.Context, id interface{}) ([]T, error) {
+	node, err := mr.treeNode(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(node.Ancestors) == 0 {
+		return []T{}, nil
+	}
+
+	return mr.Query().Where(bson.M{"_id": bson.M{"$in": node.Ancestors}}).Sort(SortBy(DepthField, Asc)).All(ctx)
+}
+
+// MoveSubtree moves id and everything below it under newParent, or to
+// the root when newParent is nil, in a transaction where the deployment
+// supports them. Moving a node below itself fails with ErrValidation.
+func (mr *MongoRepository[T]) MoveSubtree(ctx context.Context, id interface{}, newParent interface{}) error {
+	node, err := mr.treeNode(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	var target TreeNode
+
+	if newParent != nil {
+		parent, err := mr.treeNode(ctx, newParent)
+
+		if err != nil {
+			return err
+		}
+
+		for _, ancestor := range append(parent.Ancestors, newParent) {
+			if sameValue(ancestor, id) {
+				return fmt.Errorf("%w: cannot move %v below itself", ErrValidation, id)
+			}
+		}
+
+		target.SetParent(newParent, parent)
+	} else {
+		target.SetParent(nil, nil)
+	}
+
+	move := func(ctx context.Context) error {
+		repo := mr.ForRequest(ctx)
+
+		// Descendants keep their ancestors below id and shift depth.
+		err, _ := repo.UpdateManyResult(bson.M{AncestorsField: id}, mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			AncestorsField: bson.M{"$concatArrays": bson.A{
+				append(append(bson.A{}, target.Ancestors...), id),
+				bson.M{"$slice": bson.A{"$" + AncestorsField, node.Depth + 1, bson.M{"$max": bson.A{1, bson.M{"$size": "$" + AncestorsField}}}}},
+			}},
+			DepthField: bson.M{"$add": bson.A{"$" + DepthField, target.Depth - node.Depth}},
+		}}}})
+
+		if err != nil {
+			return err
+		}
+
+		err, _ = repo.UpdateOneResult(bson.M{"_id": id}, bson.M{"$set": bson.M{
+			ParentField:    target.Parent,
+			AncestorsField: target.Ancestors,
+			DepthField:     target.Depth,
+		}})
+
+		return err
+	}
+
+	transactional, err := supportsTransactions(ctx, mr.Database.Client())
+
+	if err != nil {
+		return err
+	}
+
+	if !transactional {
+		return move(ctx)
+	}
+
+	session, err := mr.Database.Client().StartSession()
+
+	if err != nil {
+		return err
+	}
+
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, move(sc)
+	})
+
+	return err
+}
@@ -0,0 +1,343 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	TwoPhaseCollection = "remongo_two_phase"
+	// PendingField lists, on every participating document, the two-phase
+	// commits that have been applied to it but not yet finished.
+	PendingField = "_pending_txns"
+)
+
+type TwoPhaseState string
+
+const (
+	TwoPhasePending   TwoPhaseState = "pending"
+	TwoPhaseApplied   TwoPhaseState = "applied"
+	TwoPhaseDone      TwoPhaseState = "done"
+	TwoPhaseCanceling TwoPhaseState = "canceling"
+	TwoPhaseCancelled TwoPhaseState = "cancelled"
+)
+
+// TwoPhaseOp updates one document. Rollback must undo Apply, e.g. an
+// $inc by the opposite amount. DocID is the _id Filter matched, recorded
+// before Apply runs; finishing and rolling back target it alone, since
+// Filter may no longer match once Apply landed.
+type TwoPhaseOp struct {
+	Collection string      `bson:"collection"`
+	Filter     bson.Raw    `bson:"filter"`
+	Apply      bson.Raw    `bson:"apply"`
+	Rollback   bson.Raw    `bson:"rollback"`
+	DocID      interface{} `bson:"doc_id,omitempty"`
+}
+
+// TwoPhaseOp builds an operation on the repository's collection. The
+// filter must match exactly one document.
+func (mr *MongoRepository[T]) TwoPhaseOp(filter interface{}, apply interface{}, rollback interface{}) (TwoPhaseOp, error) {
+	op := TwoPhaseOp{Collection: mr.CollectionName()}
+
+	for _, part := range []struct {
+		target *bson.Raw
+		value  interface{}
+	}{{&op.Filter, filter}, {&op.Apply, apply}, {&op.Rollback, rollback}} {
+		raw, err := bson.Marshal(part.value)
+
+		if err != nil {
+			return op, err
+		}
+
+		*part.target = raw
+	}
+
+	return op, nil
+}
+
+type TwoPhaseRecord struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	State     TwoPhaseState      `bson:"state"`
+	Ops       []TwoPhaseOp       `bson:"ops"`
+	Error     string             `bson:"error,omitempty"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+// TwoPhaseCommit applies updates across collections on deployments
+// without transactions, following the pending-state pattern: the intent
+// is recorded in TwoPhaseCollection, each document is updated and tagged
+// with the commit ID, and the tags are cleared once all updates landed.
+// A failure before that point rolls the applied updates back. Readers
+// may observe the intermediate state; Recover finishes or rolls back
+// commits abandoned by a crashed process. Caches of the participating
+// repositories are not invalidated.
+type TwoPhaseCommit struct {
+	Database *mongo.Database
+}
+
+func InitTwoPhaseCommit(database *mongo.Database) *TwoPhaseCommit {
+	return &TwoPhaseCommit{Database: database}
+}
+
+func (tp *TwoPhaseCommit) records() *mongo.Collection {
+	return tp.Database.Collection(TwoPhaseCollection)
+}
+
+// Execute applies ops as one unit and returns the commit ID.
+func (tp *TwoPhaseCommit) Execute(ctx context.Context, ops ...TwoPhaseOp) (primitive.ObjectID, error) {
+	record := &TwoPhaseRecord{
+		ID:        primitive.NewObjectID(),
+		State:     TwoPhasePending,
+		Ops:       ops,
+		UpdatedAt: time.Now(),
+	}
+
+	if _, err := tp.records().InsertOne(ctx, record); err != nil {
+		return record.ID, err
+	}
+
+	if err := tp.apply(ctx, record); err != nil {
+		record.Error = err.Error()
+
+		if rollbackErr := tp.rollback(ctx, record); rollbackErr != nil {
+			return record.ID, fmt.Errorf("%w; rollback: %v", err, rollbackErr)
+		}
+
+		return record.ID, err
+	}
+
+	return record.ID, tp.finish(ctx, record)
+}
+
+// Recover completes applied commits and rolls back pending or canceling
+// ones that were last updated more than olderThan ago.
+func (tp *TwoPhaseCommit) Recover(ctx context.Context, olderThan time.Duration) (int, error) {
+	cursor, err := tp.records().Find(ctx, bson.M{
+		"state":      bson.M{"$in": bson.A{TwoPhasePending, TwoPhaseApplied, TwoPhaseCanceling}},
+		"updated_at": bson.M{"$lt": time.Now().Add(-olderThan)},
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	records := []TwoPhaseRecord{}
+
+	if err = cursor.All(ctx, &records); err != nil {
+		return 0, err
+	}
+
+	for i := range records {
+		record := &records[i]
+
+		if record.State == TwoPhaseApplied {
+			err = tp.finish(ctx, record)
+		} else {
+			err = tp.rollback(ctx, record)
+		}
+
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(records), nil
+}
+
+func (tp *TwoPhaseCommit) apply(ctx context.Context, record *TwoPhaseRecord) error {
+	for i := range record.Ops {
+		op := &record.Ops[i]
+
+		if err := tp.resolve(ctx, record, i); err != nil {
+			return err
+		}
+
+		filter := bson.D{{Key: "$and", Value: bson.A{
+			op.Filter,
+			bson.M{"_id": op.DocID, PendingField: bson.M{"$ne": record.ID}},
+		}}}
+
+		update, err := withOperator(op.Apply, "$push", PendingField, record.ID)
+
+		if err != nil {
+			return err
+		}
+
+		result, err := tp.Database.Collection(op.Collection).UpdateOne(ctx, filter, update)
+
+		if err != nil {
+			return err
+		}
+
+		if result.MatchedCount == 0 {
+			applied, err := tp.tagged(ctx, *op, record.ID)
+
+			if err != nil {
+				return err
+			}
+
+			if !applied {
+				return fmt.Errorf("%w: %s %s", ErrNotFound, op.Collection, op.Filter)
+			}
+		}
+	}
+
+	return tp.setState(ctx, record, TwoPhaseApplied)
+}
+
+// resolve records the _id the filter of record.Ops[i] matches before the
+// update is applied, so that a crash in between still leaves the document
+// to roll back.
+func (tp *TwoPhaseCommit) resolve(ctx context.Context, record *TwoPhaseRecord, i int) error {
+	op := &record.Ops[i]
+
+	if op.DocID != nil {
+		return nil
+	}
+
+	var doc struct {
+		ID interface{} `bson:"_id"`
+	}
+
+	err := tp.Database.Collection(op.Collection).
+		FindOne(ctx, op.Filter, options.FindOne().SetProjection(bson.M{"_id": 1})).
+		Decode(&doc)
+
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("%w: %s %s", ErrNotFound, op.Collection, op.Filter)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tp.records().UpdateOne(ctx, bson.M{"_id": record.ID}, bson.M{"$set": bson.M{
+		fmt.Sprintf("ops.%d.doc_id", i): doc.ID,
+	}})
+
+	if err != nil {
+		return err
+	}
+
+	op.DocID = doc.ID
+
+	return nil
+}
+
+func (tp *TwoPhaseCommit) finish(ctx context.Context, record *TwoPhaseRecord) error {
+	for _, op := range record.Ops {
+		if err := tp.settle(ctx, op, record.ID, bson.M{"$pull": bson.M{PendingField: record.ID}}); err != nil {
+			return err
+		}
+	}
+
+	return tp.setState(ctx, record, TwoPhaseDone)
+}
+
+func (tp *TwoPhaseCommit) rollback(ctx context.Context, record *TwoPhaseRecord) error {
+	if err := tp.setState(ctx, record, TwoPhaseCanceling); err != nil {
+		return err
+	}
+
+	for _, op := range record.Ops {
+		// Without an _id the op never got to its update.
+		if op.DocID == nil {
+			continue
+		}
+
+		update, err := withOperator(op.Rollback, "$pull", PendingField, record.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if err = tp.settle(ctx, op, record.ID, update); err != nil {
+			return err
+		}
+	}
+
+	return tp.setState(ctx, record, TwoPhaseCancelled)
+}
+
+// settle runs update on the document of op while it is tagged with id. A
+// document that is no longer tagged was settled already or never
+// applied; one that is gone fails with ErrNotFound.
+func (tp *TwoPhaseCommit) settle(ctx context.Context, op TwoPhaseOp, id primitive.ObjectID, update interface{}) error {
+	collection := tp.Database.Collection(op.Collection)
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": op.DocID, PendingField: id}, update)
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	count, err := collection.CountDocuments(ctx, bson.M{"_id": op.DocID})
+
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return fmt.Errorf("%w: %s %v", ErrNotFound, op.Collection, op.DocID)
+	}
+
+	return nil
+}
+
+func (tp *TwoPhaseCommit) tagged(ctx context.Context, op TwoPhaseOp, id primitive.ObjectID) (bool, error) {
+	count, err := tp.Database.Collection(op.Collection).CountDocuments(ctx, bson.M{"_id": op.DocID, PendingField: id})
+
+	return count > 0, err
+}
+
+func (tp *TwoPhaseCommit) setState(ctx context.Context, record *TwoPhaseRecord, state TwoPhaseState) error {
+	record.State = state
+	record.UpdatedAt = time.Now()
+
+	_, err := tp.records().UpdateOne(ctx, bson.M{"_id": record.ID}, bson.M{"$set": bson.M{
+		"state":      record.State,
+		"error":      record.Error,
+		"updated_at": record.UpdatedAt,
+	}})
+
+	return err
+}
+
+// withOperator adds field: value under operator to an update document,
+// merging with an operator already present.
+func withOperator(update bson.Raw, operator string, field string, value interface{}) (bson.D, error) {
+	doc := bson.D{}
+
+	if len(update) > 0 {
+		if err := bson.Unmarshal(update, &doc); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, e := range doc {
+		if e.Key != operator {
+			continue
+		}
+
+		fields, ok := e.Value.(bson.D)
+
+		if !ok {
+			return nil, fmt.Errorf("remongo: %s must be a document", operator)
+		}
+
+		doc[i].Value = append(fields, bson.E{Key: field, Value: value})
+
+		return doc, nil
+	}
+
+	return append(doc, bson.E{Key: operator, Value: bson.D{{Key: field, Value: value}}}), nil
+}
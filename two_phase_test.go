@@ -0,0 +1,88 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestTwoPhaseRollbackTargetsAppliedDocument(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("rollback", func(mt *mtest.T) {
+		updated := bson.E{Key: "n", Value: 1}
+
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(updated),
+			mtest.CreateCursorResponse(0, "db.accounts", mtest.FirstBatch, bson.D{{Key: "_id", Value: "A"}}),
+			mtest.CreateSuccessResponse(updated),
+			mtest.CreateSuccessResponse(updated),
+			mtest.CreateCursorResponse(0, "db.accounts", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(updated),
+			mtest.CreateSuccessResponse(updated),
+			mtest.CreateSuccessResponse(updated),
+		)
+
+		debit := twoPhaseOp(mt.T,
+			bson.M{"_id": "A", "balance": bson.M{"$gte": 100}},
+			bson.M{"$inc": bson.M{"balance": -100}},
+			bson.M{"$inc": bson.M{"balance": 100}},
+		)
+		credit := twoPhaseOp(mt.T,
+			bson.M{"_id": "B"},
+			bson.M{"$inc": bson.M{"balance": 100}},
+			bson.M{"$inc": bson.M{"balance": -100}},
+		)
+
+		id, err := InitTwoPhaseCommit(mt.DB).Execute(context.Background(), debit, credit)
+
+		if !errors.Is(err, ErrNotFound) {
+			mt.Fatalf("want ErrNotFound, got %v", err)
+		}
+
+		updates := []bson.Raw{}
+
+		for _, event := range mt.GetAllStartedEvents() {
+			if event.CommandName == "update" && event.Command.Lookup("update").StringValue() == "accounts" {
+				updates = append(updates, event.Command.Lookup("updates").Array().Index(0).Value().Document())
+			}
+		}
+
+		if len(updates) != 2 {
+			mt.Fatalf("want apply and rollback updates, got %d", len(updates))
+		}
+
+		filter := updates[1].Lookup("q").Document()
+
+		if filter.Lookup("_id").StringValue() != "A" || filter.Lookup(PendingField).ObjectID() != id {
+			mt.Fatalf("want rollback filtered on _id and tag, got %v", filter)
+		}
+
+		if _, err = filter.LookupErr("balance"); err == nil {
+			mt.Fatalf("rollback must not repeat the apply guard, got %v", filter)
+		}
+
+		if amount := updates[1].Lookup("u", "$inc", "balance").Int32(); amount != 100 {
+			mt.Fatalf("want the debit undone, got %d", amount)
+		}
+	})
+}
+
+func twoPhaseOp(t *testing.T, filter, apply, rollback interface{}) TwoPhaseOp {
+	op := TwoPhaseOp{Collection: "accounts"}
+
+	for target, value := range map[*bson.Raw]interface{}{&op.Filter: filter, &op.Apply: apply, &op.Rollback: rollback} {
+		raw, err := bson.Marshal(value)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		*target = raw
+	}
+
+	return op
+}
@@ -0,0 +1,24 @@
+package remongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureUnique creates an ascending unique index over fields and returns
+// its name. Violations surface from writes as *DuplicateKeyError.
+func (mr *MongoRepository[T]) EnsureUnique(ctx context.Context, fields ...string) (string, error) {
+	keys := bson.D{}
+
+	for _, field := range fields {
+		keys = append(keys, bson.E{Key: field, Value: 1})
+	}
+
+	return mr.collection(ctx).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetUnique(true),
+	})
+}
@@ -0,0 +1,21 @@
+package remongo
+
+import "go.mongodb.org/mongo-driver/mongo"
+
+// UpdateResult tells "no match" (MatchedCount == 0) apart from "matched
+// but unchanged" (MatchedCount > 0, ModifiedCount == 0).
+type UpdateResult struct {
+	MatchedCount  int64
+	ModifiedCount int64
+	UpsertedCount int64
+	UpsertedID    interface{}
+}
+
+func newUpdateResult(result *mongo.UpdateResult) *UpdateResult {
+	return &UpdateResult{
+		MatchedCount:  result.MatchedCount,
+		ModifiedCount: result.ModifiedCount,
+		UpsertedCount: result.UpsertedCount,
+		UpsertedID:    result.UpsertedID,
+	}
+}
@@ -0,0 +1,92 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type UpsertStatus string
+
+const (
+	UpsertInserted UpsertStatus = "inserted"
+	UpsertUpdated  UpsertStatus = "updated"
+	UpsertFailed   UpsertStatus = "failed"
+)
+
+// UpsertOutcome is the result for models[Index]. ID is set for inserted
+// documents and Err for failed ones.
+type UpsertOutcome struct {
+	Index  int
+	Status UpsertStatus
+	ID     interface{}
+	Err    error
+}
+
+// UpsertMany replaces or inserts every model keyed on keyFields in one
+// unordered bulk write, so a failing document does not stop the others.
+// The returned error is only set when the bulk write could not run.
+func (mr *MongoRepository[T]) UpsertMany(ctx context.Context, models []T, keyFields ...string) ([]UpsertOutcome, error) {
+	outcomes := make([]UpsertOutcome, len(models))
+	writes := []mongo.WriteModel{}
+	indexes := []int{}
+
+	for i := range models {
+		outcomes[i] = UpsertOutcome{Index: i, Status: UpsertUpdated}
+
+		doc, err := mr.prepareDocument(ctx, &models[i])
+
+		if err == nil {
+			var filter bson.D
+
+			if filter, err = keyFilter(doc.(bson.Raw), keyFields); err == nil {
+				writes = append(writes, mongo.NewReplaceOneModel().
+					SetFilter(filter).
+					SetReplacement(doc).
+					SetUpsert(true))
+				indexes = append(indexes, i)
+
+				continue
+			}
+		}
+
+		outcomes[i].Status, outcomes[i].Err = UpsertFailed, translateError(err)
+	}
+
+	if len(writes) == 0 {
+		return outcomes, nil
+	}
+
+	var result *mongo.BulkWriteResult
+
+	err := mr.runContext(ctx, "UpsertMany", nil, nil, func(ctx context.Context, op *Operation) error {
+		return mr.write(ctx, Idempotent, func() (err error) {
+			result, err = mr.collection(ctx).BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+
+			return err
+		})
+	})
+
+	var bwe mongo.BulkWriteException
+
+	if err != nil && !errors.As(err, &bwe) {
+		return nil, err
+	}
+
+	for _, writeErr := range bwe.WriteErrors {
+		i := indexes[writeErr.Index]
+		outcomes[i].Status, outcomes[i].Err = UpsertFailed, translateWriteError(writeErr.WriteError)
+	}
+
+	if result != nil {
+		for index, id := range result.UpsertedIDs {
+			i := indexes[index]
+			outcomes[i].Status, outcomes[i].ID = UpsertInserted, id
+		}
+	}
+
+	return outcomes, nil
+}
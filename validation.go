@@ -0,0 +1,197 @@
+package remongo
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Validator checks a model before it is written. TagValidator is the
+// built-in implementation; adapters for other libraries can be plugged in
+// through MongoRepository.Validator.
+type Validator interface {
+	Validate(model interface{}) error
+}
+
+type FieldError struct {
+	Field string
+	Rule  string
+	Param string
+}
+
+func (fe FieldError) Error() string {
+	if fe.Param != "" {
+		return fmt.Sprintf("%s failed %s=%s", fe.Field, fe.Rule, fe.Param)
+	}
+
+	return fmt.Sprintf("%s failed %s", fe.Field, fe.Rule)
+}
+
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (ve *ValidationError) Error() string {
+	messages := make([]string, 0, len(ve.Fields))
+
+	for _, field := range ve.Fields {
+		messages = append(messages, field.Error())
+	}
+
+	return "remongo: validation failed: " + strings.Join(messages, ", ")
+}
+
+func (ve *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// TagValidator honors `validate:"..."` struct tags with the rules
+// required, email, min, max, len and oneof. Nested structs are checked
+// and reported with dotted bson paths.
+type TagValidator struct{}
+
+func (TagValidator) Validate(model interface{}) error {
+	fields := validateStruct(reflect.ValueOf(model), "")
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+
+	return nil
+}
+
+func (mr *MongoRepository[T]) validate(model interface{}) error {
+	if mr.Validator == nil {
+		return nil
+	}
+
+	return mr.Validator.Validate(model)
+}
+
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+func validateStruct(v reflect.Value, prefix string) []FieldError {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	errs := []FieldError{}
+
+	for _, field := range bsonFields(v.Type()) {
+		value, err := v.FieldByIndexErr(field.Index)
+
+		if err != nil {
+			continue
+		}
+
+		path := prefix + field.Name
+		sf := v.Type().FieldByIndex(field.Index)
+
+		if tag := sf.Tag.Get("validate"); tag != "" && tag != "-" {
+			errs = append(errs, validateField(value, path, tag)...)
+		}
+
+		if indirectType(field.Type).Kind() == reflect.Struct && indirectType(field.Type).PkgPath() != "time" {
+			errs = append(errs, validateStruct(value, path+".")...)
+		}
+	}
+
+	return errs
+}
+
+func validateField(value reflect.Value, path string, tag string) []FieldError {
+	errs := []FieldError{}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		if name != "required" && value.IsZero() {
+			continue
+		}
+
+		if !checkRule(value, name, param) {
+			errs = append(errs, FieldError{Field: path, Rule: name, Param: param})
+		}
+	}
+
+	return errs
+}
+
+func checkRule(value reflect.Value, rule string, param string) bool {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return rule != "required"
+		}
+
+		value = value.Elem()
+	}
+
+	switch rule {
+	case "required":
+		return !value.IsZero()
+	case "email":
+		return value.Kind() == reflect.String && emailPattern.MatchString(value.String())
+	case "min", "max", "len":
+		limit, err := strconv.ParseFloat(param, 64)
+
+		if err != nil {
+			return false
+		}
+
+		size, ok := measure(value)
+
+		if !ok {
+			return false
+		}
+
+		switch rule {
+		case "min":
+			return size >= limit
+		case "max":
+			return size <= limit
+		default:
+			return size == limit
+		}
+	case "oneof":
+		actual := fmt.Sprint(value.Interface())
+
+		for _, option := range strings.Fields(param) {
+			if option == actual {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// measure returns the length of strings and collections and the value of
+// numbers, which is what min, max and len compare against.
+func measure(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(len([]rune(value.String()))), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+
+	return 0, false
+}
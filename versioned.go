@@ -0,0 +1,580 @@
+package remongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DocumentVersion is one entry of a document's history.
+type DocumentVersion struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	DocID    bson.RawValue      `bson:"doc_id"`
+	Version  int64              `bson:"version"`
+	At       time.Time          `bson:"at"`
+	Op       string             `bson:"op"`
+	Deleted  bool               `bson:"deleted"`
+	Document bson.Raw           `bson:"doc"`
+}
+
+type FieldChange struct {
+	Path   string
+	Before interface{}
+	After  interface{}
+}
+
+// VersionedRepository records a full copy of every document version in
+// the History collection on each replace, update and delete done through
+// it. The state before a document's first recorded change is stored as
+// its version 1. Recording is not transactional: a crash between the
+// write and the history insert loses that version.
+type VersionedRepository[T IMongoModel] struct {
+	IMongoRepository[T]
+	History *mongo.Collection
+	// LockField names the optimistic-lock counter that Revert increments.
+	LockField string
+
+	repo *MongoRepository[T]
+}
+
+func NewVersionedRepository[T IMongoModel](repo *MongoRepository[T], historyCollection string) *VersionedRepository[T] {
+	return &VersionedRepository[T]{
+		IMongoRepository: repo,
+		History:          repo.Database.Collection(historyCollection),
+		repo:             repo,
+	}
+}
+
+// rewrap keeps the copies returned by the embedded repository recording
+// history.
+func (vr *VersionedRepository[T]) rewrap(repo IMongoRepository[T]) IMongoRepository[T] {
+	clone := *vr
+	clone.IMongoRepository = repo
+
+	if mr, ok := repo.(*MongoRepository[T]); ok {
+		clone.repo = mr
+	}
+
+	return &clone
+}
+
+func (vr *VersionedRepository[T]) WithDryRun() IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.WithDryRun())
+}
+
+func (vr *VersionedRepository[T]) WithContext(ctx context.Context) IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.WithContext(ctx))
+}
+
+func (vr *VersionedRepository[T]) ForRequest(ctx context.Context) IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.ForRequest(ctx))
+}
+
+func (vr *VersionedRepository[T]) WithHint(hint interface{}) IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.WithHint(hint))
+}
+
+func (vr *VersionedRepository[T]) WithQueryName(name string) IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.WithQueryName(name))
+}
+
+func (vr *VersionedRepository[T]) WithBatchSize(size int32) IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.WithBatchSize(size))
+}
+
+func (vr *VersionedRepository[T]) WithNoCursorTimeout() IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.WithNoCursorTimeout())
+}
+
+func (vr *VersionedRepository[T]) On(collection string) IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.On(collection))
+}
+
+func (vr *VersionedRepository[T]) WithDatabase(database *mongo.Database) IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.WithDatabase(database))
+}
+
+func (vr *VersionedRepository[T]) With(opts ...RepositoryOption) IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.With(opts...))
+}
+
+func (vr *VersionedRepository[T]) WithCheckpoint(name string) IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.WithCheckpoint(name))
+}
+
+func (vr *VersionedRepository[T]) Scoped(names ...string) IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.Scoped(names...))
+}
+
+func (vr *VersionedRepository[T]) Unfiltered() IMongoRepository[T] {
+	return vr.rewrap(vr.IMongoRepository.Unfiltered())
+}
+
+// EnsureHistoryIndexes creates the index the history lookups rely on.
+func (vr *VersionedRepository[T]) EnsureHistoryIndexes(ctx context.Context) error {
+	_, err := vr.History.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "doc_id", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return err
+}
+
+func (vr *VersionedRepository[T]) ReplaceOne(filter interface{}, model *T, opts ...*options.ReplaceOptions) (error, int64) {
+	var modified int64
+
+	err := vr.record("ReplaceOne", filter, 1, func() (err error) {
+		err, modified = vr.IMongoRepository.ReplaceOne(filter, model, opts...)
+
+		return err
+	})
+
+	return err, modified
+}
+
+func (vr *VersionedRepository[T]) UpdateOne(filter interface{}, update interface{}, opts ...*options.UpdateOptions) (error, int64) {
+	err, result := vr.UpdateOneResult(filter, update, opts...)
+
+	return err, result.ModifiedCount
+}
+
+func (vr *VersionedRepository[T]) UpdateOneResult(
+	filter interface{},
+	update interface{},
+	opts ...*options.UpdateOptions,
+) (error, *UpdateResult) {
+	result := &UpdateResult{}
+
+	err := vr.record("UpdateOne", filter, 1, func() (err error) {
+		err, result = vr.IMongoRepository.UpdateOneResult(filter, update, opts...)
+
+		return err
+	})
+
+	return err, result
+}
+
+func (vr *VersionedRepository[T]) UpdateMany(filter interface{}, update interface{}, opts ...*options.UpdateOptions) (error, int64) {
+	err, result := vr.UpdateManyResult(filter, update, opts...)
+
+	return err, result.ModifiedCount
+}
+
+func (vr *VersionedRepository[T]) UpdateManyResult(
+	filter interface{},
+	update interface{},
+	opts ...*options.UpdateOptions,
+) (error, *UpdateResult) {
+	result := &UpdateResult{}
+
+	err := vr.record("UpdateMany", filter, 0, func() (err error) {
+		err, result = vr.IMongoRepository.UpdateManyResult(filter, update, opts...)
+
+		return err
+	})
+
+	return err, result
+}
+
+func (vr *VersionedRepository[T]) DeleteOne(filter interface{}, opts ...*options.DeleteOptions) (error, int64) {
+	var deleted int64
+
+	err := vr.record("DeleteOne", filter, 1, func() (err error) {
+		err, deleted = vr.IMongoRepository.DeleteOne(filter, opts...)
+
+		return err
+	})
+
+	return err, deleted
+}
+
+func (vr *VersionedRepository[T]) DeleteMany(filter interface{}, opts ...*options.DeleteOptions) (error, int64) {
+	var deleted int64
+
+	err := vr.record("DeleteMany", filter, 0, func() (err error) {
+		err, deleted = vr.IMongoRepository.DeleteMany(filter, opts...)
+
+		return err
+	})
+
+	return err, deleted
+}
+
+// record snapshots the documents matching filter, runs write and appends
+// the resulting version of each of them to the history.
+func (vr *VersionedRepository[T]) record(op string, filter interface{}, limit int64, write func() error) error {
+	if vr.repo.DryRun {
+		return write()
+	}
+
+	ctx := vr.repo.context()
+
+	before, err := vr.snapshot(ctx, filter, limit)
+
+	if err != nil {
+		return vr.repo.wrapError(op, filter, err)
+	}
+
+	for _, doc := range before {
+		if err = vr.ensureBaseline(ctx, doc); err != nil {
+			return vr.repo.wrapError(op, filter, err)
+		}
+	}
+
+	if err = write(); err != nil {
+		return err
+	}
+
+	deleted := op == "DeleteOne" || op == "DeleteMany"
+
+	for _, doc := range before {
+		id := doc.Lookup("_id")
+		current := doc
+
+		if !deleted {
+			if current, err = vr.current(ctx, id); err != nil {
+				return vr.repo.wrapError(op, filter, err)
+			}
+
+			if current == nil {
+				continue
+			}
+		}
+
+		if _, err = vr.appendVersion(ctx, id, op, current, deleted); err != nil {
+			return vr.repo.wrapError(op, filter, err)
+		}
+	}
+
+	return nil
+}
+
+func (vr *VersionedRepository[T]) snapshot(ctx context.Context, filter interface{}, limit int64) ([]bson.Raw, error) {
+	query, err := toFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find()
+
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+
+	cursor, err := vr.repo.collection(ctx).Find(ctx, query, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	docs := []bson.Raw{}
+
+	return docs, cursor.All(ctx, &docs)
+}
+
+func (vr *VersionedRepository[T]) current(ctx context.Context, id bson.RawValue) (bson.Raw, error) {
+	raw, err := vr.repo.collection(ctx).FindOne(ctx, bson.M{"_id": id}).Raw()
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+
+	return raw, err
+}
+
+func (vr *VersionedRepository[T]) ensureBaseline(ctx context.Context, doc bson.Raw) error {
+	id := doc.Lookup("_id")
+
+	count, err := vr.History.CountDocuments(ctx, bson.M{"doc_id": id}, options.Count().SetLimit(1))
+
+	if err != nil || count > 0 {
+		return err
+	}
+
+	_, err = vr.appendVersion(ctx, id, "Baseline", doc, false)
+
+	return err
+}
+
+func (vr *VersionedRepository[T]) appendVersion(
+	ctx context.Context,
+	id bson.RawValue,
+	op string,
+	doc bson.Raw,
+	deleted bool,
+) (int64, error) {
+	latest, err := vr.latestVersion(ctx, id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	version := DocumentVersion{
+		ID:       primitive.NewObjectID(),
+		DocID:    id,
+		Version:  latest + 1,
+		At:       time.Now(),
+		Op:       op,
+		Deleted:  deleted,
+		Document: doc,
+	}
+
+	_, err = vr.History.InsertOne(ctx, version)
+
+	return version.Version, err
+}
+
+func (vr *VersionedRepository[T]) latestVersion(ctx context.Context, id bson.RawValue) (int64, error) {
+	var latest DocumentVersion
+
+	err := vr.History.FindOne(
+		ctx,
+		bson.M{"doc_id": id},
+		options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}),
+	).Decode(&latest)
+
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+
+	return latest.Version, err
+}
+
+func (vr *VersionedRepository[T]) docID(id interface{}) (interface{}, error) {
+	return vr.repo.parseID(id)
+}
+
+// Revert makes the given version the current document again, restoring
+// it if it was deleted, and records the result as a new version. The
+// LockField counter continues from the current document instead of
+// going back with the rest of the fields.
+func (vr *VersionedRepository[T]) Revert(ctx context.Context, id interface{}, version int64) error {
+	docID, err := vr.docID(id)
+
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": docID}
+
+	return vr.repo.runContext(ctx, "Revert", filter, nil, func(ctx context.Context, op *Operation) error {
+		var entry DocumentVersion
+
+		err := vr.History.FindOne(ctx, bson.M{"doc_id": docID, "version": version}).Decode(&entry)
+
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("%w: version %d", ErrNotFound, version)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if entry.Deleted {
+			return fmt.Errorf("remongo: version %d records a deletion", version)
+		}
+
+		current, err := vr.current(ctx, entry.DocID)
+
+		if err != nil {
+			return err
+		}
+
+		if current != nil {
+			if err = vr.ensureBaseline(ctx, current); err != nil {
+				return err
+			}
+		}
+
+		doc, err := vr.revision(entry.Document, current)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = vr.repo.collection(ctx).ReplaceOne(ctx, bson.M{"_id": entry.DocID}, doc, options.Replace().SetUpsert(true))
+
+		if err != nil {
+			return err
+		}
+
+		raw, err := bson.Marshal(doc)
+
+		if err == nil {
+			_, err = vr.appendVersion(ctx, entry.DocID, "Revert", raw, false)
+		}
+
+		return err
+	})
+}
+
+// revision is the stored version with LockField set one past the value
+// of the current document, or of the version when there is none.
+func (vr *VersionedRepository[T]) revision(stored bson.Raw, current bson.Raw) (bson.D, error) {
+	elements, err := stored.Elements()
+
+	if err != nil {
+		return nil, err
+	}
+
+	doc := bson.D{}
+
+	for _, element := range elements {
+		if element.Key() != vr.LockField {
+			doc = append(doc, bson.E{Key: element.Key(), Value: element.Value()})
+		}
+	}
+
+	if vr.LockField == "" {
+		return doc, nil
+	}
+
+	source := stored
+
+	if current != nil {
+		source = current
+	}
+
+	counter, _ := source.Lookup(vr.LockField).AsInt64OK()
+
+	return append(doc, bson.E{Key: vr.LockField, Value: counter + 1}), nil
+}
+
+// ListVersions returns the history of the document with id, oldest first.
+func (vr *VersionedRepository[T]) ListVersions(ctx context.Context, id interface{}) ([]DocumentVersion, error) {
+	docID, err := vr.docID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := vr.History.Find(
+		ctx,
+		bson.M{"doc_id": docID},
+		options.Find().SetSort(bson.D{{Key: "version", Value: 1}}),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []DocumentVersion{}
+
+	return versions, cursor.All(ctx, &versions)
+}
+
+// GetVersion returns the given version of the document, or nil when the
+// version does not exist or records its deletion.
+func (vr *VersionedRepository[T]) GetVersion(ctx context.Context, id interface{}, version int64) (*T, error) {
+	docID, err := vr.docID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return vr.findVersion(ctx, bson.M{"doc_id": docID, "version": version}, nil)
+}
+
+// GetVersionAt returns the document as it was at the given time, or nil
+// when it did not exist or was deleted at that point.
+func (vr *VersionedRepository[T]) GetVersionAt(ctx context.Context, id interface{}, at time.Time) (*T, error) {
+	docID, err := vr.docID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return vr.findVersion(
+		ctx,
+		bson.M{"doc_id": docID, "at": bson.M{"$lte": at}},
+		options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}),
+	)
+}
+
+func (vr *VersionedRepository[T]) findVersion(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*T, error) {
+	var version DocumentVersion
+
+	if opts == nil {
+		opts = options.FindOne()
+	}
+
+	err := vr.History.FindOne(ctx, filter, opts).Decode(&version)
+
+	if err == mongo.ErrNoDocuments || (err == nil && version.Deleted) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	model := new(T)
+
+	return model, vr.repo.decode(ctx, version.Document, model)
+}
+
+// Diff lists the fields that differ between two versions of a document,
+// using dotted paths for nested documents.
+func (vr *VersionedRepository[T]) Diff(ctx context.Context, id interface{}, from int64, to int64) ([]FieldChange, error) {
+	docID, err := vr.docID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	docs := map[int64]bson.Raw{}
+
+	for _, version := range []int64{from, to} {
+		var entry DocumentVersion
+
+		err = vr.History.FindOne(ctx, bson.M{"doc_id": docID, "version": version}).Decode(&entry)
+
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%w: version %d", ErrNotFound, version)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		docs[version] = entry.Document
+	}
+
+	return diffRaw(docs[from], docs[to]), nil
+}
+
+func diffDocuments(prefix string, before bson.M, after bson.M) []FieldChange {
+	changes := []FieldChange{}
+
+	for key, old := range before {
+		path := prefix + key
+		updated, ok := after[key]
+
+		oldDoc, oldIsDoc := old.(bson.M)
+		newDoc, newIsDoc := updated.(bson.M)
+
+		switch {
+		case !ok:
+			changes = append(changes, FieldChange{Path: path, Before: old})
+		case oldIsDoc && newIsDoc:
+			changes = append(changes, diffDocuments(path+".", oldDoc, newDoc)...)
+		case !reflect.DeepEqual(old, updated):
+			changes = append(changes, FieldChange{Path: path, Before: old, After: updated})
+		}
+	}
+
+	for key, updated := range after {
+		if _, ok := before[key]; !ok {
+			changes = append(changes, FieldChange{Path: prefix + key, After: updated})
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,151 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrStreamInvalidated = errors.New("remongo: change stream invalidated")
+
+// InvalidatePolicy decides what Watch does after the watched collection
+// is dropped or renamed, which ends a change stream with an invalidate
+// event.
+type InvalidatePolicy int
+
+const (
+	// InvalidateFail returns ErrStreamInvalidated.
+	InvalidateFail InvalidatePolicy = iota
+	// InvalidateRestart opens a new stream right after the invalidate
+	// event, so changes to a recreated collection are not missed.
+	InvalidateRestart
+	// InvalidateResync calls WatchOptions.Resync and then continues from
+	// the position taken before it, like Projector.Rebuild.
+	InvalidateResync
+)
+
+type WatchOptions struct {
+	Pipeline     mongo.Pipeline
+	ResumeAfter  bson.Raw
+	OnInvalidate InvalidatePolicy
+	Resync       func(ctx context.Context) error
+}
+
+// Watch passes the change events of the collection to handler until ctx
+// is cancelled or handler fails. Drop and rename events are delivered
+// before the stream is re-established according to OnInvalidate; the
+// invalidate event itself is not. Every event's ID is its resume token.
+func (mr *MongoRepository[T]) Watch(ctx context.Context, handler ChangeHandler, opts ...*WatchOptions) error {
+	ctx, done, err := beginWorker(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	watch := WatchOptions{}
+
+	for _, opt := range opts {
+		if opt != nil {
+			watch = *opt
+		}
+	}
+
+	if watch.Pipeline == nil {
+		watch.Pipeline = mongo.Pipeline{}
+	}
+
+	streamOpts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+	if watch.ResumeAfter != nil {
+		streamOpts.SetResumeAfter(watch.ResumeAfter)
+	}
+
+	for {
+		invalidated, err := mr.watchOnce(ctx, watch.Pipeline, streamOpts, handler)
+
+		if err != nil || invalidated == nil {
+			return err
+		}
+
+		streamOpts = options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+		switch watch.OnInvalidate {
+		case InvalidateRestart:
+			streamOpts.SetStartAfter(invalidated)
+		case InvalidateResync:
+			token, err := mr.streamPosition(ctx, watch.Pipeline)
+
+			if err != nil {
+				return err
+			}
+
+			if watch.Resync != nil {
+				if err = watch.Resync(ctx); err != nil {
+					return err
+				}
+			}
+
+			streamOpts.SetResumeAfter(token)
+		default:
+			return ErrStreamInvalidated
+		}
+	}
+}
+
+// watchOnce consumes one stream and returns the resume token of the
+// invalidate event that ended it, if any.
+func (mr *MongoRepository[T]) watchOnce(
+	ctx context.Context,
+	pipeline mongo.Pipeline,
+	opts *options.ChangeStreamOptions,
+	handler ChangeHandler,
+) (bson.Raw, error) {
+	stream, err := mr.collection(ctx).Watch(ctx, pipeline, opts)
+
+	if err != nil {
+		return nil, mr.wrapError("Watch", nil, err)
+	}
+
+	defer stream.Close(ctx)
+
+	for stream.Next(ctx) {
+		var event ChangeEvent
+
+		if err = stream.Decode(&event); err != nil {
+			return nil, err
+		}
+
+		if event.OperationType == "invalidate" {
+			return event.ID, nil
+		}
+
+		if err = handler(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, nil
+	}
+
+	return nil, mr.wrapError("Watch", nil, stream.Err())
+}
+
+// streamPosition returns a resume token for the current end of the
+// oplog.
+func (mr *MongoRepository[T]) streamPosition(ctx context.Context, pipeline mongo.Pipeline) (bson.Raw, error) {
+	stream, err := mr.collection(ctx).Watch(ctx, pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	token := stream.ResumeToken()
+
+	return token, stream.Close(ctx)
+}
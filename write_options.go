@@ -0,0 +1,111 @@
+package remongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// WriteOptions tunes write durability for a single repository instead of
+// relying on the client-wide settings.
+type WriteOptions struct {
+	// RetryWrites retries idempotent writes that failed because of a
+	// network error or a missing primary, up to MaxRetries times (one by
+	// default). RetryBackoff, 100ms by default, doubles between attempts.
+	// Replaces, single updates and deletes only count as idempotent when
+	// their filter pins the _id.
+	RetryWrites  bool
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// WriteConcern replaces the database write concern for the collection.
+	WriteConcern *writeconcern.WriteConcern
+	// WTimeout bounds how long the server waits for WriteConcern.
+	WTimeout time.Duration
+	// Fallback accepts writes acknowledged by the primary when WriteConcern
+	// times out or cannot be satisfied, instead of returning the error.
+	Fallback bool
+}
+
+func (wo *WriteOptions) collectionOptions() *options.CollectionOptions {
+	opts := options.Collection()
+
+	if wo == nil {
+		return opts
+	}
+
+	wc := wo.WriteConcern
+
+	if wo.WTimeout > 0 {
+		if wc == nil {
+			wc = writeconcern.Majority()
+		}
+
+		wc = &writeconcern.WriteConcern{W: wc.W, Journal: wc.Journal, WTimeout: wo.WTimeout}
+	}
+
+	if wc != nil {
+		opts.SetWriteConcern(wc)
+	}
+
+	return opts
+}
+
+func (mr *MongoRepository[T]) write(ctx context.Context, idempotency Idempotency, fn func() error) error {
+	err := fn()
+
+	if err != nil && idempotency != Unsafe && mr.WriteOptions != nil && mr.WriteOptions.RetryWrites {
+		err = mr.WriteOptions.retry(ctx, idempotency, err, fn)
+	}
+
+	if err != nil && mr.WriteOptions != nil && mr.WriteOptions.Fallback && isWriteConcernOnly(err) {
+		return nil
+	}
+
+	return translateError(err)
+}
+
+func (wo *WriteOptions) retry(ctx context.Context, idempotency Idempotency, err error, fn func() error) error {
+	retries, backoff := wo.MaxRetries, wo.RetryBackoff
+
+	if retries <= 0 {
+		retries = 1
+	}
+
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	for attempt := 0; attempt < retries && isFailover(err); attempt++ {
+		if sleepErr := sleepContext(ctx, backoff<<attempt); sleepErr != nil {
+			return err
+		}
+
+		err = fn()
+
+		if idempotency == IdempotentInsert && isDuplicateID(err) {
+			return nil
+		}
+	}
+
+	return err
+}
+
+func isWriteConcernOnly(err error) bool {
+	var we mongo.WriteException
+
+	if errors.As(err, &we) {
+		return we.WriteConcernError != nil && len(we.WriteErrors) == 0
+	}
+
+	var bwe mongo.BulkWriteException
+
+	if errors.As(err, &bwe) {
+		return bwe.WriteConcernError != nil && len(bwe.WriteErrors) == 0
+	}
+
+	return false
+}